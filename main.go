@@ -6,7 +6,6 @@ import (
 	"github.com/gregbiv/news-api/pkg/command"
 	"github.com/gregbiv/news-api/pkg/command/migration"
 	"github.com/gregbiv/news-api/pkg/config"
-	"github.com/mattes/migrate/database"
 	"github.com/mitchellh/cli"
 	log "github.com/sirupsen/logrus"
 )
@@ -34,16 +33,11 @@ func main() {
 		log.Debugf("Initialized with config: %+v", conf)
 	}
 
-	db, err := database.Open(conf.Database.PostgresDB.DSN)
-	if err != nil {
-		log.Fatalf("Cannot initialize db with the following DSN: %s", conf.Database.PostgresDB.DSN)
-	}
-
 	c := &cli.CLI{
 		Name:     "news-api",
 		Version:  "dev",
 		HelpFunc: cli.BasicHelpFunc("news-api"),
-		Commands: commands(conf, db),
+		Commands: commands(conf),
 		Args:     os.Args[1:],
 	}
 
@@ -55,13 +49,15 @@ func main() {
 	os.Exit(exitStatus)
 }
 
-func commands(conf *config.Specification, db database.Driver) map[string]cli.CommandFactory {
+func commands(conf *config.Specification) map[string]cli.CommandFactory {
+	ui := &cli.BasicUi{
+		Reader:      os.Stdin,
+		Writer:      os.Stdout,
+		ErrorWriter: os.Stderr,
+	}
+
 	meta := command.Meta{
-		UI: &cli.BasicUi{
-			Reader:      os.Stdin,
-			Writer:      os.Stdout,
-			ErrorWriter: os.Stderr,
-		},
+		UI:     ui,
 		Config: conf,
 	}
 
@@ -71,15 +67,21 @@ func commands(conf *config.Specification, db database.Driver) map[string]cli.Com
 				Meta: meta,
 			}, nil
 		},
-		"migrate": func() (cli.Command, error) {
-			cmd := migration.CreateMigrateCommand(
-				db,
-				conf.Migration.Dir,
-				conf.Migration.Version,
-			)
-			return cmd, nil
+		"purge": func() (cli.Command, error) {
+			return &command.PurgeCommand{
+				Meta: meta,
+			}, nil
+		},
+		"dispatch": func() (cli.Command, error) {
+			return &command.DispatchCommand{
+				Meta: meta,
+			}, nil
 		},
 	}
 
+	for name, factory := range migration.CreateMigrateCommands(ui, conf.Database, conf.Migration.Dir) {
+		cf[name] = factory
+	}
+
 	return cf
 }
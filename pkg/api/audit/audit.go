@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"encoding/json"
+	"github.com/gregbiv/news-api/pkg/model"
+	"time"
+)
+
+type (
+	// entry describes a news-api API audit entry
+	entry struct {
+		ID           int64           `json:"id"`
+		Actor        string          `json:"actor"`
+		ClaimedActor string          `json:"claimed_actor,omitempty"`
+		Action       string          `json:"action"`
+		ResourceType string          `json:"resource_type"`
+		ResourceID   string          `json:"resource_id"`
+		Diff         json.RawMessage `json:"diff"`
+		RequestID    string          `json:"request_id,omitempty"`
+		CreatedAt    time.Time       `json:"created_at"`
+	}
+
+	// entryList describes a page of audit entries
+	entryList struct {
+		Result []entry `json:"result"`
+	}
+)
+
+func (e *entry) fromModel(modelEntry *model.AuditEntry) {
+	e.ID = modelEntry.ID
+	e.Actor = modelEntry.Actor
+	e.ClaimedActor = modelEntry.ClaimedActor
+	e.Action = modelEntry.Action
+	e.ResourceType = modelEntry.ResourceType
+	e.ResourceID = modelEntry.ResourceID
+	e.Diff = modelEntry.Diff
+	e.RequestID = modelEntry.RequestID
+	e.CreatedAt = modelEntry.CreatedAt
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageAudit "github.com/gregbiv/news-api/pkg/storage/audit"
+	"net/http"
+)
+
+type listAuditHandler struct {
+	lister storageAudit.Lister
+}
+
+// NewListAuditHandler init and returns an instance of listAuditHandler
+func NewListAuditHandler(lister storageAudit.Lister) http.Handler {
+	return &listAuditHandler{
+		lister: lister,
+	}
+}
+
+func (h *listAuditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	resourceType := q.Get("resource")
+	if resourceType == "" {
+		api.RenderErrMissingURIParam(w, r, "resource")
+		return
+	}
+
+	resourceID := q.Get("id")
+	if resourceID == "" {
+		api.RenderErrMissingURIParam(w, r, "id")
+		return
+	}
+
+	skip, top, err := api.FetchPagination(r)
+	if err != nil {
+		api.RenderInvalidPagination(w, r, err)
+		return
+	}
+
+	modelEntries, err := h.lister.ListByResource(r.Context(), resourceType, resourceID, skip, top)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := entryList{Result: make([]entry, len(modelEntries))}
+	for i, modelEntry := range modelEntries {
+		response.Result[i].fromModel(modelEntry)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,329 @@
+package category
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/context"
+	"github.com/gregbiv/news-api/pkg/identifier"
+	"github.com/gregbiv/news-api/pkg/middleware"
+	"github.com/gregbiv/news-api/pkg/storage"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// maxBatchSize is the maximum amount of operations of a batch
+const maxBatchSize = 100
+
+type (
+	batchCategoryHandler struct {
+		storer    storageCategory.Storer
+		updater   storageCategory.Updater
+		discarder storageCategory.Discarder
+		batcher   storageCategory.Batcher
+		assigner  *identifier.Assigner
+	}
+
+	// batchOperation describes a single operation of a batch request
+	batchOperation struct {
+		Op       string   `json:"op"`
+		Category category `json:"category"`
+		// document is the category as sent by the client, for it to be validated against the schemas
+		document json.RawMessage
+	}
+
+	// batchResult describes the outcome of a single operation, in the order of the request
+	batchResult struct {
+		Status   int        `json:"status"`
+		Category *category  `json:"category,omitempty"`
+		Error    *api.Error `json:"error,omitempty"`
+	}
+
+	// batchResponse describes the outcome of a batch request
+	batchResponse struct {
+		Result []batchResult `json:"result"`
+	}
+)
+
+// NewBatchCategoryHandler init and returns an instance of batchCategoryHandler
+func NewBatchCategoryHandler(
+	storer storageCategory.Storer,
+	updater storageCategory.Updater,
+	discarder storageCategory.Discarder,
+	batcher storageCategory.Batcher,
+	assigner *identifier.Assigner,
+) http.Handler {
+	return &batchCategoryHandler{
+		storer:    storer,
+		updater:   updater,
+		discarder: discarder,
+		batcher:   batcher,
+		assigner:  assigner,
+	}
+}
+
+// ServeHTTP applies several category operations at once. By default all of them are applied in a
+// single transaction, with atomic=false every operation is applied on its own and reported separately.
+func (h *batchCategoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic := true
+	if value := r.URL.Query().Get("atomic"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			api.RenderInvalidInput(w, r, "atomic", "InvalidAtomic", "The 'atomic' query parameter must be a boolean.")
+			return
+		}
+		atomic = parsed
+	}
+
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		context.Logger(r.Context()).Info(err)
+		api.RenderInvalidInput(w, r, "", "InvalidBody", ErrInvalidBody.Error())
+		return
+	}
+
+	var operations []batchOperation
+	if err := json.Unmarshal(body, &operations); err != nil {
+		api.RenderInvalidInput(w, r, "", "InvalidBody", ErrInvalidBody.Error())
+		return
+	}
+
+	if len(operations) == 0 || len(operations) > maxBatchSize {
+		api.RenderInvalidInput(w, r, "", "InvalidBatchSize", "A batch requires between 1 and %d operations.", maxBatchSize)
+		return
+	}
+
+	if atomic {
+		h.applyAtomic(w, r, operations)
+		return
+	}
+
+	response := batchResponse{Result: make([]batchResult, len(operations))}
+	for i := range operations {
+		response.Result[i] = h.apply(r, &operations[i])
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
+
+// applyAtomic applies all the operations or none of them, the first failing operation is rendered
+func (h *batchCategoryHandler) applyAtomic(w http.ResponseWriter, r *http.Request, operations []batchOperation) {
+	storageOperations := make([]storageCategory.Operation, len(operations))
+	for i := range operations {
+		if invalid := operations[i].prepare(h.assigner); invalid != nil {
+			renderOperationError(w, r, i, http.StatusBadRequest, invalid)
+			return
+		}
+		storageOperations[i] = operations[i].toStorage()
+	}
+
+	err := h.batcher.Batch(r.Context(), storageOperations)
+	if err != nil {
+		batchErr, ok := err.(*storageCategory.BatchError)
+		if !ok {
+			api.RenderError(w, r, err)
+			return
+		}
+
+		// Failures that are not caused by the operation itself fail the whole request
+		status, apiErr := operationError(batchErr.Err)
+		if status >= http.StatusInternalServerError {
+			api.RenderError(w, r, batchErr.Err)
+			return
+		}
+
+		renderOperationError(w, r, batchErr.Index, status, apiErr)
+		return
+	}
+
+	response := batchResponse{Result: make([]batchResult, len(operations))}
+	for i, operation := range operations {
+		response.Result[i] = operation.succeeded(storageOperations[i])
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
+
+// apply applies a single operation on its own
+func (h *batchCategoryHandler) apply(r *http.Request, operation *batchOperation) batchResult {
+	if invalid := operation.prepare(h.assigner); invalid != nil {
+		return batchResult{Status: http.StatusBadRequest, Error: invalid}
+	}
+
+	storageOperation := operation.toStorage()
+
+	var err error
+	switch storageOperation.Op {
+	case storageCategory.OperationCreate:
+		err = h.storer.Store(r.Context(), storageOperation.Category)
+	case storageCategory.OperationUpdate:
+		err = h.updater.Update(r.Context(), storageOperation.Category, storageOperation.Version)
+	case storageCategory.OperationDelete:
+		err = h.discarder.Discard(r.Context(), storageOperation.Category.CategoryID)
+	}
+
+	if err != nil {
+		status, apiErr := operationError(err)
+		if status >= http.StatusInternalServerError {
+			context.Logger(r.Context()).Error(err)
+		}
+		return batchResult{Status: status, Error: apiErr}
+	}
+
+	return operation.succeeded(storageOperation)
+}
+
+// operationSchemas are the request schemas the categories of the operations are validated against
+var operationSchemas = map[string]string{
+	storageCategory.OperationCreate: "create_category.json",
+	storageCategory.OperationUpdate: "update_category.json",
+}
+
+// UnmarshalJSON decodes the operation and keeps the category document as sent
+func (o *batchOperation) UnmarshalJSON(data []byte) error {
+	type operation batchOperation
+	if err := json.Unmarshal(data, (*operation)(o)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Category json.RawMessage `json:"category"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	o.document = raw.Category
+
+	return nil
+}
+
+// prepare assigns the ID of the created category and returns why the operation cannot be applied, if so
+func (o *batchOperation) prepare(assigner *identifier.Assigner) *api.Error {
+	invalid := func(target, message string) *api.Error {
+		return &api.Error{Code: "InvalidInput", Target: target, Message: message}
+	}
+
+	switch o.Op {
+	case storageCategory.OperationCreate, storageCategory.OperationUpdate, storageCategory.OperationDelete:
+	default:
+		return invalid("op", "The 'op' field must be one of create, update or delete.")
+	}
+
+	// The categories are as valid as the ones of single creates and updates have to be
+	if schema, ok := operationSchemas[o.Op]; ok {
+		document := o.document
+		if len(document) == 0 {
+			document = json.RawMessage("null")
+		}
+
+		validationErrs, err := middleware.SchemaValidationErrors(schema, document)
+		if err != nil {
+			return invalid("category", err.Error())
+		}
+		if len(validationErrs) > 0 {
+			invalidErr := invalid("category", "The category is invalid.")
+			invalidErr.Details = validationErrs
+			return invalidErr
+		}
+	}
+
+	if o.Op == storageCategory.OperationCreate {
+		categoryID, err := assigner.Assign(o.Category.CategoryID)
+		if err != nil {
+			return invalid("category.category_id", err.Error())
+		}
+		o.Category.CategoryID = &categoryID
+	}
+
+	if o.Category.CategoryID == nil {
+		return invalid("category.category_id", "The 'category_id' field is required.")
+	}
+
+	if o.Op == storageCategory.OperationDelete {
+		return nil
+	}
+
+	if o.Category.Name == "" || o.Category.Title == "" {
+		return invalid("category", "The 'name' and 'title' fields are required.")
+	}
+
+	if o.Op == storageCategory.OperationUpdate && o.Category.Version == nil {
+		return invalid("category.version", "The 'version' field is required.")
+	}
+
+	return nil
+}
+
+func (o *batchOperation) toStorage() storageCategory.Operation {
+	modelCategory := o.Category.toModel()
+
+	operation := storageCategory.Operation{
+		Op:       o.Op,
+		Category: &modelCategory,
+	}
+
+	if o.Category.Version != nil {
+		operation.Version = *o.Category.Version
+	}
+
+	return operation
+}
+
+// succeeded returns the result of the applied operation
+func (o *batchOperation) succeeded(operation storageCategory.Operation) batchResult {
+	if o.Op == storageCategory.OperationDelete {
+		return batchResult{Status: http.StatusNoContent}
+	}
+
+	response := &category{}
+	if err := response.fromDB(operation.Category); err != nil {
+		return batchResult{Status: http.StatusInternalServerError, Error: &api.Error{Code: "InternalError", Message: err.Error()}}
+	}
+
+	if o.Op == storageCategory.OperationCreate {
+		return batchResult{Status: http.StatusCreated, Category: response}
+	}
+
+	return batchResult{Status: http.StatusOK, Category: response}
+}
+
+// operationError maps the error of an operation to its status code
+func operationError(err error) (int, *api.Error) {
+	storageErr := storage.AsError(err)
+
+	target := ""
+	if storageErr.Field != "" {
+		target = "category." + storageErr.Field
+	}
+
+	switch storageErr.Kind {
+	case storage.NotFound:
+		return http.StatusNotFound, &api.Error{Code: "NotFound", Target: target, Message: storageErr.Message}
+	case storage.Conflict:
+		return http.StatusConflict, &api.Error{Code: "Conflict", Target: target, Message: storageErr.Message}
+	case storage.Constraint:
+		return http.StatusUnprocessableEntity, &api.Error{Code: "UnprocessableEntity", Target: target, Message: storageErr.Message}
+	case storage.Unavailable:
+		return http.StatusServiceUnavailable, &api.Error{Code: "Unavailable", Message: storageErr.Message}
+	case storage.Timeout:
+		return http.StatusGatewayTimeout, &api.Error{Code: "Timeout", Message: storageErr.Message}
+	}
+
+	return http.StatusInternalServerError, &api.Error{Code: "InternalError", Message: err.Error()}
+}
+
+// renderOperationError renders the error of the operation that failed the batch
+func renderOperationError(w http.ResponseWriter, r *http.Request, index, status int, apiErr *api.Error) {
+	target := fmt.Sprintf("[%d]", index)
+	if apiErr.Target != "" {
+		target += "." + apiErr.Target
+	}
+	apiErr.Target = target
+	api.RenderErrResponse(w, r, &api.ErrResponse{Errors: *apiErr, HTTPStatusCode: status})
+}
@@ -0,0 +1,144 @@
+package category
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gregbiv/news-api/pkg/identifier"
+	"github.com/gregbiv/news-api/pkg/model"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	businessID = "4b2b5a3c-4a4f-4bd9-a5c4-5e1e2a8b7c6d"
+	sportsID   = "9d3f3e8c-2b1a-4c5d-8e7f-6a5b4c3d2e1f"
+)
+
+type fakeCategoryStorage struct {
+	discarded []string
+	batched   []storageCategory.Operation
+	batchErr  error
+}
+
+func (f *fakeCategoryStorage) Store(ctx context.Context, c *model.Category) error {
+	c.Version = 1
+	return nil
+}
+
+func (f *fakeCategoryStorage) Update(ctx context.Context, c *model.Category, version int64) error {
+	if version != 3 {
+		return storageCategory.ErrVersionConflict
+	}
+	c.Version = version + 1
+	return nil
+}
+
+func (f *fakeCategoryStorage) UpdateColumns(ctx context.Context, c *model.Category, version int64, columns []string) error {
+	return f.Update(ctx, c, version)
+}
+
+func (f *fakeCategoryStorage) Discard(ctx context.Context, ID string) error {
+	if ID != businessID {
+		return storageCategory.ErrCategoryNotFound
+	}
+	f.discarded = append(f.discarded, ID)
+	return nil
+}
+
+func (f *fakeCategoryStorage) Batch(ctx context.Context, operations []storageCategory.Operation) error {
+	f.batched = operations
+	return f.batchErr
+}
+
+func serveBatch(storage *fakeCategoryStorage, query, body string) *httptest.ResponseRecorder {
+	generator, _ := identifier.NewGenerator(identifier.StrategyV4)
+	handler := NewBatchCategoryHandler(storage, storage, storage, storage, identifier.NewAssigner(generator, true))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/v1/category/$batch"+query, strings.NewReader(body)))
+
+	return w
+}
+
+func TestBatchCategoryHandler(t *testing.T) {
+	t.Parallel()
+
+	operations := `[
+		{"op":"create","category":{"category_id":"` + sportsID + `","name":"sports","title":"Sports"}},
+		{"op":"update","category":{"category_id":"` + businessID + `","name":"business","title":"Business","version":2}},
+		{"op":"delete","category":{"category_id":"` + sportsID + `"}},
+		{"op":"delete","category":{"category_id":"` + businessID + `"}}
+	]`
+
+	t.Run("It reports every operation on its own when not atomic", func(t *testing.T) {
+		storage := &fakeCategoryStorage{}
+		w := serveBatch(storage, "?atomic=false", operations)
+
+		response := batchResponse{}
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		statuses := make([]int, len(response.Result))
+		for i, result := range response.Result {
+			statuses[i] = result.Status
+		}
+		assert.Equal(t, []int{http.StatusCreated, http.StatusConflict, http.StatusNotFound, http.StatusNoContent}, statuses)
+		assert.Equal(t, "Conflict", response.Result[1].Error.Code)
+		assert.Equal(t, []string{businessID}, storage.discarded)
+	})
+
+	t.Run("It applies every operation in a single batch", func(t *testing.T) {
+		storage := &fakeCategoryStorage{}
+		w := serveBatch(storage, "", operations)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, storage.batched, 4)
+		assert.Equal(t, int64(2), storage.batched[1].Version)
+	})
+
+	t.Run("It renders the operation that failed the batch", func(t *testing.T) {
+		storage := &fakeCategoryStorage{
+			batchErr: &storageCategory.BatchError{Index: 2, Err: storageCategory.ErrCategoryNotFound},
+		}
+		w := serveBatch(storage, "", operations)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"target":"[2].category.category_id"`)
+	})
+
+	t.Run("It validates every operation before applying the batch", func(t *testing.T) {
+		storage := &fakeCategoryStorage{}
+		w := serveBatch(storage, "", `[{"op":"create","category":{"category_id":"`+sportsID+`"}},{"op":"move"}]`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"target":"[0].category"`)
+		assert.Nil(t, storage.batched)
+	})
+
+	t.Run("It generates the IDs of the created categories", func(t *testing.T) {
+		storage := &fakeCategoryStorage{}
+		w := serveBatch(storage, "", `[{"op":"create","category":{"name":"sports","title":"Sports"}}]`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, storage.batched[0].Category.CategoryID)
+	})
+
+	t.Run("It keeps the categories as sent for them to be validated against the schemas", func(t *testing.T) {
+		var decoded []batchOperation
+		assert.NoError(t, json.Unmarshal([]byte(`[{"op":"create","category":{"name":"sports","extra":1}},{"op":"delete"}]`), &decoded))
+
+		assert.Equal(t, "sports", decoded[0].Category.Name)
+		assert.JSONEq(t, `{"name":"sports","extra":1}`, string(decoded[0].document))
+		assert.Empty(t, decoded[1].document)
+	})
+
+	t.Run("It rejects empty batches", func(t *testing.T) {
+		w := serveBatch(&fakeCategoryStorage{}, "", `[]`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
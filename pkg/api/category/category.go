@@ -3,10 +3,13 @@ package category
 import (
 	"encoding/json"
 	"errors"
+	"github.com/gregbiv/news-api/pkg/api"
 	"github.com/gregbiv/news-api/pkg/model"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
 	"github.com/satori/go.uuid"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 // ErrInvalidBody represents the error when the request body is invalid.
@@ -18,6 +21,8 @@ type (
 		CategoryID *uuid.UUID `json:"category_id"`
 		Name       string     `json:"name"`
 		Title      string     `json:"title"`
+		Version    *int64     `json:"version,omitempty"`
+		DeletedAt  *time.Time `json:"deleted_at,omitempty"`
 	}
 )
 
@@ -30,14 +35,22 @@ func (c *category) fromDB(dbCategory *model.Category) error {
 	c.CategoryID = &id
 	c.Name = dbCategory.Name
 	c.Title = dbCategory.Title
+	version := dbCategory.Version
+	c.Version = &version
+	c.DeletedAt = dbCategory.DeletedAt
 
 	return nil
 }
 
 func (c *category) toModel() (modelCategory model.Category) {
-	modelCategory.CategoryID = c.CategoryID.String()
+	if c.CategoryID != nil {
+		modelCategory.CategoryID = c.CategoryID.String()
+	}
 	modelCategory.Name = c.Name
 	modelCategory.Title = c.Title
+	if c.Version != nil {
+		modelCategory.Version = *c.Version
+	}
 
 	return modelCategory
 }
@@ -56,3 +69,31 @@ func (c *category) fromRequest(r *http.Request) error {
 
 	return nil
 }
+
+// renderUpdateError renders the error of a category update, version conflicts along with the current category
+func renderUpdateError(w http.ResponseWriter, r *http.Request, getter storageCategory.Getter, ID string, err error) {
+	if err == storageCategory.ErrVersionConflict {
+		renderVersionConflict(w, r, getter, ID)
+		return
+	}
+
+	api.RenderError(w, r, err)
+}
+
+// renderVersionConflict responds with the current representation of the category
+// so the client can merge its changes and retry with the latest version
+func renderVersionConflict(w http.ResponseWriter, r *http.Request, getter storageCategory.Getter, ID string) {
+	dbCategory, err := getter.GetCategoryByID(r.Context(), ID)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	current := category{}
+	if err := current.fromDB(dbCategory); err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	api.RenderConflict(w, r, "version", storageCategory.ErrVersionConflict.Error(), current)
+}
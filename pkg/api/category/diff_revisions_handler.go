@@ -0,0 +1,75 @@
+package category
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/storage"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"net/http"
+)
+
+type diffRevisionsHandler struct {
+	getter         storageCategory.Getter
+	revisionGetter storageCategory.RevisionGetter
+	urlExtractor   api.URLExtractor
+}
+
+// NewDiffRevisionsHandler init and returns an instance of diffRevisionsHandler
+func NewDiffRevisionsHandler(
+	getter storageCategory.Getter,
+	revisionGetter storageCategory.RevisionGetter,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &diffRevisionsHandler{
+		getter:         getter,
+		revisionGetter: revisionGetter,
+		urlExtractor:   urlExtractor,
+	}
+}
+
+func (h *diffRevisionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	itemID, err := h.urlExtractor.UUIDFromRoute(r, "category_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	from, err := h.urlExtractor.Int64FromRoute(r, "revision")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	to, err := h.urlExtractor.Int64FromRoute(r, "other_revision")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	// The revisions of a discarded category are not found, the same way its list is not
+	if _, err := h.getter.GetCategoryByID(r.Context(), itemID.String()); err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	fromRevision, err := h.revisionGetter.GetRevision(r.Context(), itemID.String(), from)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	toRevision, err := h.revisionGetter.GetRevision(r.Context(), itemID.String(), to)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	changes, err := storage.Diff(contentOf(fromRevision), contentOf(toRevision))
+	if err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, revisionDiff{From: from, To: to, Changes: changes})
+}
@@ -34,23 +34,15 @@ func (h *discardCategoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	dbCategory, err := h.getter.GetCategoryByID(itemID.String())
+	dbCategory, err := h.getter.GetCategoryByID(r.Context(), itemID.String())
 	if err != nil {
-		if err == storageCategory.ErrCategoryNotFound {
-			api.NotFound(w, r)
-			return
-		}
-		api.RenderInternalServerError(w, r, err)
+		api.RenderError(w, r, err)
 		return
 	}
 
-	err = h.discarder.Discard(dbCategory.CategoryID)
+	err = h.discarder.Discard(r.Context(), dbCategory.CategoryID)
 	if err != nil {
-		if err == storageCategory.ErrCategoryNotFound {
-			api.NotFound(w, r)
-			return
-		}
-		api.RenderInternalServerError(w, r, err)
+		api.RenderError(w, r, err)
 		return
 	}
 
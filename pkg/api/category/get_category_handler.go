@@ -3,6 +3,7 @@ package category
 import (
 	"github.com/go-chi/render"
 	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/context"
 	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
 	"net/http"
 )
@@ -33,23 +34,24 @@ func (h *getCategoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dbCategory, err := h.getter.GetCategoryByID(itemID.String())
-	if err != nil {
-		if err == storageCategory.ErrCategoryNotFound {
-			api.NotFound(w, r)
+	getCategory := h.getter.GetCategoryByID
+	if r.URL.Query().Get("include_deleted") == "true" {
+		if !context.IsAdmin(r.Context()) {
+			api.RenderForbidden(w, r, "include_deleted", "Only administrators can request deleted categories.")
 			return
 		}
-		api.RenderInternalServerError(w, r, err)
+		getCategory = h.getter.GetCategoryByIDIncludingDeleted
+	}
+
+	dbCategory, err := getCategory(r.Context(), itemID.String())
+	if err != nil {
+		api.RenderError(w, r, err)
 		return
 	}
 
 	modelCategory := category{}
 	err = modelCategory.fromDB(dbCategory)
 	if err != nil {
-		if err == storageCategory.ErrCategoryNotFound {
-			api.NotFound(w, r)
-			return
-		}
 		api.RenderInternalServerError(w, r, err)
 		return
 	}
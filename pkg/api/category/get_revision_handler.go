@@ -0,0 +1,59 @@
+package category
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"net/http"
+)
+
+type getRevisionHandler struct {
+	getter         storageCategory.Getter
+	revisionGetter storageCategory.RevisionGetter
+	urlExtractor   api.URLExtractor
+}
+
+// NewGetRevisionHandler init and returns an instance of getRevisionHandler
+func NewGetRevisionHandler(
+	getter storageCategory.Getter,
+	revisionGetter storageCategory.RevisionGetter,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &getRevisionHandler{
+		getter:         getter,
+		revisionGetter: revisionGetter,
+		urlExtractor:   urlExtractor,
+	}
+}
+
+func (h *getRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	itemID, err := h.urlExtractor.UUIDFromRoute(r, "category_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	number, err := h.urlExtractor.Int64FromRoute(r, "revision")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	// The revisions of a discarded category are not found, the same way its list is not
+	if _, err := h.getter.GetCategoryByID(r.Context(), itemID.String()); err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	modelRevision, err := h.revisionGetter.GetRevision(r.Context(), itemID.String(), number)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := revision{}
+	response.fromModel(modelRevision)
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,60 @@
+package category
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"net/http"
+)
+
+type listRevisionsHandler struct {
+	getter         storageCategory.Getter
+	revisionGetter storageCategory.RevisionGetter
+	urlExtractor   api.URLExtractor
+}
+
+// NewListRevisionsHandler init and returns an instance of listRevisionsHandler
+func NewListRevisionsHandler(
+	getter storageCategory.Getter,
+	revisionGetter storageCategory.RevisionGetter,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &listRevisionsHandler{
+		getter:         getter,
+		revisionGetter: revisionGetter,
+		urlExtractor:   urlExtractor,
+	}
+}
+
+func (h *listRevisionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	itemID, err := h.urlExtractor.UUIDFromRoute(r, "category_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	skip, top, err := api.FetchPagination(r)
+	if err != nil {
+		api.RenderInvalidPagination(w, r, err)
+		return
+	}
+
+	if _, err := h.getter.GetCategoryByID(r.Context(), itemID.String()); err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	modelRevisions, err := h.revisionGetter.ListRevisions(r.Context(), itemID.String(), skip, top)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := revisionList{Result: make([]revision, len(modelRevisions))}
+	for i, modelRevision := range modelRevisions {
+		response.Result[i].fromModel(modelRevision)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
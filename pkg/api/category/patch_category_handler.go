@@ -0,0 +1,179 @@
+package category
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/evanphx/json-patch"
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/context"
+	"github.com/gregbiv/news-api/pkg/middleware"
+	"github.com/gregbiv/news-api/pkg/model"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/satori/go.uuid"
+	"io/ioutil"
+	"mime"
+	"net/http"
+)
+
+const (
+	// MergePatchMediaType is the media type of a JSON Merge Patch document (RFC 7396)
+	MergePatchMediaType = "application/merge-patch+json"
+	// JSONPatchMediaType is the media type of a JSON Patch document (RFC 6902)
+	JSONPatchMediaType = "application/json-patch+json"
+)
+
+// errUnsupportedPatch represents the error when the patch document has an unknown media type.
+var errUnsupportedPatch = errors.New("Unsupported patch media type")
+
+type (
+	patchCategoryHandler struct {
+		getter       storageCategory.Getter
+		updater      storageCategory.Updater
+		urlExtractor api.URLExtractor
+	}
+)
+
+// NewPatchCategoryHandler init and returns an instance of patchCategoryHandler
+func NewPatchCategoryHandler(
+	getter storageCategory.Getter,
+	updater storageCategory.Updater,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &patchCategoryHandler{
+		getter:       getter,
+		updater:      updater,
+		urlExtractor: urlExtractor,
+	}
+}
+
+func (h *patchCategoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := h.urlExtractor.UUIDFromRoute(r, "category_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != MergePatchMediaType && mediaType != JSONPatchMediaType) {
+		api.RenderUnsupportedMediaType(w, r, r.Header.Get("Content-Type"))
+		return
+	}
+
+	defer r.Body.Close()
+	patch, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		context.Logger(r.Context()).Info(err)
+		api.RenderInvalidInput(w, r, "", "InvalidBody", ErrInvalidBody.Error())
+		return
+	}
+
+	dbCategory, err := h.getter.GetCategoryByID(r.Context(), categoryID.String())
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	current := category{}
+	if err := current.fromDB(dbCategory); err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	document, err := json.Marshal(current)
+	if err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	patched, err := applyPatch(mediaType, document, patch)
+	if err != nil {
+		api.RenderUnprocessableEntity(w, r, "", err.Error())
+		return
+	}
+
+	// The patched category has to be as valid as a fully replaced one
+	if !middleware.ValidateJSONSchema(w, r, "update_category.json", patched) {
+		return
+	}
+
+	categoryAPI := category{}
+	if err := json.Unmarshal(patched, &categoryAPI); err != nil {
+		api.RenderInvalidInput(w, r, "", "InvalidBody", ErrInvalidBody.Error())
+		return
+	}
+
+	if categoryAPI.CategoryID == nil || !uuid.Equal(*categoryAPI.CategoryID, *categoryID) {
+		api.RenderInvalidInput(w, r, "category_id", "ImmutableCategoryID", "The 'category_id' field cannot be changed.")
+		return
+	}
+
+	// Clients may assert the version they patch through the patch document itself,
+	// otherwise the version that was just read is expected
+	version := dbCategory.Version
+	if categoryAPI.Version != nil {
+		version = *categoryAPI.Version
+	}
+
+	modelCategory := categoryAPI.toModel()
+
+	columns := changedColumns(dbCategory, &modelCategory)
+	if len(columns) == 0 {
+		if version != dbCategory.Version {
+			renderVersionConflict(w, r, h.getter, dbCategory.CategoryID)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, current)
+		return
+	}
+
+	err = h.updater.UpdateColumns(r.Context(), &modelCategory, version, columns)
+	if err != nil {
+		renderUpdateError(w, r, h.getter, dbCategory.CategoryID, err)
+		return
+	}
+
+	response := category{}
+	err = response.fromDB(&modelCategory)
+	if err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
+
+// applyPatch applies either a JSON Merge Patch or a JSON Patch to the given document
+func applyPatch(mediaType string, document, patch []byte) ([]byte, error) {
+	switch mediaType {
+	case MergePatchMediaType:
+		return jsonpatch.MergePatch(document, patch)
+	case JSONPatchMediaType:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, err
+		}
+
+		return operations.Apply(document)
+	}
+
+	return nil, errUnsupportedPatch
+}
+
+// changedColumns returns the category columns that differ between both models
+func changedColumns(stored, patched *model.Category) []string {
+	var columns []string
+
+	if stored.Name != patched.Name {
+		columns = append(columns, storageCategory.ColumnName)
+	}
+
+	if stored.Title != patched.Title {
+		columns = append(columns, storageCategory.ColumnTitle)
+	}
+
+	return columns
+}
@@ -0,0 +1,74 @@
+package category
+
+import (
+	"github.com/gregbiv/news-api/pkg/model"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	document := []byte(`{"category_id":"4b2b5a3c-4a4f-4bd9-a5c4-5e1e2a8b7c6d","name":"business","title":"Business","version":3}`)
+
+	t.Run("It applies a JSON Merge Patch", func(t *testing.T) {
+		patched, err := applyPatch(MergePatchMediaType, document, []byte(`{"title":"Business news"}`))
+
+		assert.NoError(t, err)
+		assert.JSONEq(
+			t,
+			`{"category_id":"4b2b5a3c-4a4f-4bd9-a5c4-5e1e2a8b7c6d","name":"business","title":"Business news","version":3}`,
+			string(patched),
+		)
+	})
+
+	t.Run("It applies a JSON Patch", func(t *testing.T) {
+		patched, err := applyPatch(
+			JSONPatchMediaType,
+			document,
+			[]byte(`[{"op":"test","path":"/version","value":3},{"op":"replace","path":"/name","value":"economy"}]`),
+		)
+
+		assert.NoError(t, err)
+		assert.JSONEq(
+			t,
+			`{"category_id":"4b2b5a3c-4a4f-4bd9-a5c4-5e1e2a8b7c6d","name":"economy","title":"Business","version":3}`,
+			string(patched),
+		)
+	})
+
+	t.Run("It fails when a JSON Patch test operation fails", func(t *testing.T) {
+		_, err := applyPatch(
+			JSONPatchMediaType,
+			document,
+			[]byte(`[{"op":"test","path":"/version","value":2}]`),
+		)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("It fails on unknown media types", func(t *testing.T) {
+		_, err := applyPatch("application/json", document, []byte(`{}`))
+
+		assert.Equal(t, errUnsupportedPatch, err)
+	})
+}
+
+func TestChangedColumns(t *testing.T) {
+	t.Parallel()
+
+	stored := &model.Category{Name: "business", Title: "Business"}
+
+	assert.Empty(t, changedColumns(stored, &model.Category{Name: "business", Title: "Business"}))
+	assert.Equal(
+		t,
+		[]string{storageCategory.ColumnTitle},
+		changedColumns(stored, &model.Category{Name: "business", Title: "Business news"}),
+	)
+	assert.Equal(
+		t,
+		[]string{storageCategory.ColumnName, storageCategory.ColumnTitle},
+		changedColumns(stored, &model.Category{Name: "economy", Title: "Economy"}),
+	)
+}
@@ -1,27 +1,28 @@
 package category
 
 import (
-	"fmt"
 	"github.com/go-chi/render"
 	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/identifier"
 	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
 	log "github.com/sirupsen/logrus"
 	"net/http"
+	"path"
 )
 
 type postCategoryHandler struct {
-	getter storageCategory.Getter
-	storer storageCategory.Storer
+	storer   storageCategory.Storer
+	assigner *identifier.Assigner
 }
 
 // NewPostCategoryHandler init and returns an instance of postCategoryHandler
 func NewPostCategoryHandler(
-	getter storageCategory.Getter,
 	storer storageCategory.Storer,
+	assigner *identifier.Assigner,
 ) http.Handler {
 	return &postCategoryHandler{
-		getter: getter,
-		storer: storer,
+		storer:   storer,
+		assigner: assigner,
 	}
 }
 
@@ -34,22 +35,38 @@ func (h *postCategoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		if err == ErrInvalidBody {
 			log.Info(err)
-			api.RenderInvalidInput(w, r, "", ErrInvalidBody.Error())
+			api.RenderInvalidInput(w, r, "", "InvalidBody", ErrInvalidBody.Error())
 			return
 		}
 		api.RenderInternalServerError(w, r, err)
 		return
 	}
 
+	// The ID is generated by the server, unless
+	// clients are allowed to supply their own
+	categoryID, err := h.assigner.Assign(categoryAPI.CategoryID)
+	if err != nil {
+		api.RenderInvalidInput(w, r, "category_id", "ClientIDNotAllowed", err.Error())
+		return
+	}
+	categoryAPI.CategoryID = &categoryID
+
 	// This category model contains all the values
 	// for the category we want to create
 	modelCategory := categoryAPI.toModel()
 
-	if err := h.storer.Store(&modelCategory); err != nil {
-		api.RenderInternalServerError(w, r, fmt.Errorf("postCategory store error: %s", err))
+	if err := h.storer.Store(r.Context(), &modelCategory); err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := category{}
+	if err := response.fromDB(&modelCategory); err != nil {
+		api.RenderInternalServerError(w, r, err)
 		return
 	}
 
-	render.Status(r, http.StatusOK)
-	render.JSON(w, r, map[string]string{"category_id": modelCategory.CategoryID})
+	w.Header().Set("Location", path.Join(r.URL.Path, modelCategory.CategoryID))
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, response)
 }
@@ -43,7 +43,7 @@ func (h *categoryUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	err = categoryAPI.fromRequest(r)
 	if err == ErrInvalidBody {
 		context.Logger(r.Context()).Info(err)
-		api.RenderInvalidInput(w, r, "", ErrInvalidBody.Error())
+		api.RenderInvalidInput(w, r, "", "InvalidBody", ErrInvalidBody.Error())
 		return
 	}
 	if err != nil {
@@ -51,9 +51,14 @@ func (h *categoryUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	dbCategory, err := h.getter.GetCategoryByID(categoryAPI.CategoryID.String())
+	if categoryAPI.Version == nil {
+		api.RenderInvalidInput(w, r, "version", "VersionRequired", "The 'version' field is required.")
+		return
+	}
+
+	dbCategory, err := h.getter.GetCategoryByID(r.Context(), categoryAPI.CategoryID.String())
 	if err != nil {
-		api.RenderInternalServerError(w, r, err)
+		api.RenderError(w, r, err)
 		return
 	}
 
@@ -63,9 +68,9 @@ func (h *categoryUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 
 	h.setCategory(&modelCategory, dbCategory)
 
-	err = h.updater.Update(dbCategory)
+	err = h.updater.Update(r.Context(), dbCategory, *categoryAPI.Version)
 	if err != nil {
-		api.RenderInternalServerError(w, r, err)
+		renderUpdateError(w, r, h.getter, dbCategory.CategoryID, err)
 		return
 	}
 
@@ -0,0 +1,58 @@
+package category
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"net/http"
+)
+
+type restoreCategoryHandler struct {
+	getter       storageCategory.Getter
+	restorer     storageCategory.Restorer
+	urlExtractor api.URLExtractor
+}
+
+// NewRestoreCategoryHandler init and returns an instance of restoreCategoryHandler
+func NewRestoreCategoryHandler(
+	getter storageCategory.Getter,
+	restorer storageCategory.Restorer,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &restoreCategoryHandler{
+		getter:       getter,
+		restorer:     restorer,
+		urlExtractor: urlExtractor,
+	}
+}
+
+func (h *restoreCategoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Validate query params
+	itemID, err := h.urlExtractor.UUIDFromRoute(r, "category_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	err = h.restorer.Restore(r.Context(), itemID.String())
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	dbCategory, err := h.getter.GetCategoryByID(r.Context(), itemID.String())
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := category{}
+	err = response.fromDB(dbCategory)
+	if err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
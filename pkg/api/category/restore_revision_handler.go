@@ -0,0 +1,85 @@
+package category
+
+import (
+	"context"
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+)
+
+type restoreRevisionHandler struct {
+	getter         storageCategory.Getter
+	revisionGetter storageCategory.RevisionGetter
+	updater        storageCategory.Updater
+	transactor     database.Transactor
+	urlExtractor   api.URLExtractor
+}
+
+// NewRestoreRevisionHandler init and returns an instance of restoreRevisionHandler
+func NewRestoreRevisionHandler(
+	getter storageCategory.Getter,
+	revisionGetter storageCategory.RevisionGetter,
+	updater storageCategory.Updater,
+	transactor database.Transactor,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &restoreRevisionHandler{
+		getter:         getter,
+		revisionGetter: revisionGetter,
+		updater:        updater,
+		transactor:     transactor,
+		urlExtractor:   urlExtractor,
+	}
+}
+
+// ServeHTTP writes a new revision of the category equal to the requested one
+func (h *restoreRevisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	itemID, err := h.urlExtractor.UUIDFromRoute(r, "category_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	number, err := h.urlExtractor.Int64FromRoute(r, "revision")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	// The revision is read and written back within a single transaction,
+	// so that the category is not changed in between
+	var dbCategory *model.Category
+	err = h.transactor.InTx(r.Context(), func(ctx context.Context, tx *sqlx.Tx) error {
+		modelRevision, err := h.revisionGetter.GetRevision(ctx, itemID.String(), number)
+		if err != nil {
+			return err
+		}
+
+		if dbCategory, err = h.getter.GetCategoryByID(ctx, itemID.String()); err != nil {
+			return err
+		}
+
+		dbCategory.Name = modelRevision.Name
+		dbCategory.Title = modelRevision.Title
+
+		return h.updater.Update(ctx, dbCategory, dbCategory.Version)
+	})
+	if err != nil {
+		renderUpdateError(w, r, h.getter, itemID.String(), err)
+		return
+	}
+
+	response := category{}
+	err = response.fromDB(dbCategory)
+	if err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,52 @@
+package category
+
+import (
+	"github.com/gregbiv/news-api/pkg/model"
+	"time"
+)
+
+type (
+	// revision describes a news-api API category revision
+	revision struct {
+		CategoryID string    `json:"category_id"`
+		Revision   int64     `json:"revision"`
+		Name       string    `json:"name"`
+		Title      string    `json:"title"`
+		Actor      string    `json:"actor"`
+		CreatedAt  time.Time `json:"created_at"`
+	}
+
+	// revisionList describes a page of category revisions
+	revisionList struct {
+		Result []revision `json:"result"`
+	}
+
+	// revisionContent holds the revision fields that are compared between revisions
+	revisionContent struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	}
+
+	// revisionDiff describes the changes between two category revisions
+	revisionDiff struct {
+		From    int64       `json:"from"`
+		To      int64       `json:"to"`
+		Changes interface{} `json:"changes"`
+	}
+)
+
+func (rev *revision) fromModel(modelRevision *model.CategoryRevision) {
+	rev.CategoryID = modelRevision.CategoryID
+	rev.Revision = modelRevision.Revision
+	rev.Name = modelRevision.Name
+	rev.Title = modelRevision.Title
+	rev.Actor = modelRevision.Actor
+	rev.CreatedAt = modelRevision.CreatedAt
+}
+
+func contentOf(modelRevision *model.CategoryRevision) revisionContent {
+	return revisionContent{
+		Name:  modelRevision.Name,
+		Title: modelRevision.Title,
+	}
+}
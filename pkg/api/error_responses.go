@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/go-chi/render"
 	"github.com/gregbiv/news-api/pkg/context"
+	"github.com/gregbiv/news-api/pkg/storage"
 	"net/http"
 )
 
@@ -21,13 +22,29 @@ type ErrResponse struct {
 
 // Error struct
 type Error struct {
-	Code    string `json:"code"`
-	Target  string `json:"target,omitempty"`
-	Message string `json:"message"`
+	Code    string      `json:"code"`
+	Target  string      `json:"target,omitempty"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+	// Errors lists the validation errors of the request, if any. They are rendered as the
+	// details of the legacy shape and as the errors extension of the problem details.
+	Errors []ValidationError `json:"-"`
+}
+
+// ValidationError describes why a value of the request body is invalid
+type ValidationError struct {
+	// Pointer is the JSON Pointer of the invalid value, i.e. /name
+	Pointer string `json:"pointer"`
+	// Keyword is the JSON schema keyword the value does not satisfy, i.e. maxLength
+	Keyword string `json:"keyword"`
+	// Constraint is the value of the keyword in the schema, i.e. 255
+	Constraint interface{} `json:"constraint,omitempty"`
+	Code       string      `json:"code"`
+	Message    string      `json:"message"`
 }
 
 // ErrRender is taking care of rendering the errors correctly
-func ErrRender(code, target, message string, statusCode int) render.Renderer {
+func ErrRender(code, target, message string, statusCode int) *ErrResponse {
 	return &ErrResponse{
 		Errors: Error{
 			Code:    code,
@@ -40,43 +57,205 @@ func ErrRender(code, target, message string, statusCode int) render.Renderer {
 
 // NotFound is being called when a invalid route is requested.
 func NotFound(w http.ResponseWriter, r *http.Request) {
-	render.Render(w, r, ErrRender("InvalidUri", "", "The requested URI does not represent any resource on the server.", http.StatusNotFound))
+	message := localize(w, r).Message("InvalidUri", "The requested URI does not represent any resource on the server.")
+
+	RenderErrResponse(w, r, ErrRender("InvalidUri", "", message, http.StatusNotFound))
 }
 
 // RenderErrMissingURIParam is being called when a query parameter is missing
 func RenderErrMissingURIParam(w http.ResponseWriter, r *http.Request, param string) {
-	render.Render(
+	message := localize(w, r).Message("MissingUriParam", fmt.Sprintf("The '%s' query parameter is required.", param), param)
+
+	RenderErrResponse(
 		w,
 		r,
-		ErrRender("MissingUriParam", "", fmt.Sprintf("The '%s' query parameter is required.", param), http.StatusBadRequest),
+		ErrRender("MissingUriParam", "", message, http.StatusBadRequest),
 	)
 }
 
-// RenderInternalServerError is being called when there is an internal server error
+// RenderError renders an error returned by the storages according to its kind. Resources that
+// do not exist are reported as an invalid URI, as the handlers address them through their route.
+func RenderError(w http.ResponseWriter, r *http.Request, err error) {
+	storageErr := storage.AsError(err)
+
+	switch storageErr.Kind {
+	case storage.NotFound:
+		NotFound(w, r)
+	case storage.Conflict:
+		RenderConflict(w, r, storageErr.Field, storageErr.Message, nil)
+	case storage.Constraint:
+		RenderUnprocessableEntity(w, r, storageErr.Field, storageErr.Message)
+	case storage.Unavailable:
+		RenderServiceUnavailable(w, r, err)
+	case storage.Timeout:
+		RenderTimeout(w, r)
+	default:
+		RenderInternalServerError(w, r, err)
+	}
+}
+
+// RenderInternalServerError is being called when there is an internal server error.
+// Errors caused by the request running out of time are rendered as a timeout instead.
 func RenderInternalServerError(w http.ResponseWriter, r *http.Request, err error) {
+	if context.TimedOut(r.Context()) {
+		RenderTimeout(w, r)
+		return
+	}
+
 	context.Logger(r.Context()).Warn(err)
 
-	render.Render(
+	RenderErrResponse(
 		w,
 		r,
 		ErrRender("InternalError", "", err.Error(), http.StatusInternalServerError),
 	)
 }
 
-// RenderInvalidInput is being called when the request input is invalid
-func RenderInvalidInput(w http.ResponseWriter, r *http.Request, target, message string) {
-	render.Render(
+// RenderInvalidInput is being called when the request input is invalid. The message is given in English
+// as a fmt format, the other languages get the message of their catalog keyed by the given code.
+// Both are formatted with the arguments.
+func RenderInvalidInput(w http.ResponseWriter, r *http.Request, target, code, message string, args ...interface{}) {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+
+	RenderErrResponse(
+		w,
+		r,
+		ErrRender("InvalidInput", target, localize(w, r).Message(code, message, args...), http.StatusBadRequest),
+	)
+}
+
+// RenderValidationErrors is being called when the request body does not match the schema of the resource.
+// The messages of the validation errors are translated according to their code and constraint.
+func RenderValidationErrors(w http.ResponseWriter, r *http.Request, validationErrs []ValidationError) {
+	catalog := localize(w, r)
+
+	localized := make([]ValidationError, len(validationErrs))
+	for i, validationErr := range validationErrs {
+		localized[i] = validationErr
+		if validationErr.Constraint != nil {
+			localized[i].Message = catalog.Message(validationErr.Code, validationErr.Message, validationErr.Constraint)
+		} else {
+			localized[i].Message = catalog.Message(validationErr.Code, validationErr.Message)
+		}
+	}
+
+	RenderErrResponse(
+		w,
+		r,
+		&ErrResponse{
+			Errors: Error{
+				Code:    "InvalidInput",
+				Message: catalog.Message("InvalidInput", "The request body is invalid."),
+				Errors:  localized,
+			},
+			HTTPStatusCode: http.StatusBadRequest,
+		},
+	)
+}
+
+// RenderUnauthorized is being called when the request requires the client to authenticate
+func RenderUnauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	RenderErrResponse(
+		w,
+		r,
+		ErrRender("Unauthorized", "Authorization", message, http.StatusUnauthorized),
+	)
+}
+
+// RenderForbidden is being called when the client is not allowed to perform the request
+func RenderForbidden(w http.ResponseWriter, r *http.Request, target, message string) {
+	RenderErrResponse(
+		w,
+		r,
+		ErrRender("Forbidden", target, message, http.StatusForbidden),
+	)
+}
+
+// RenderUnsupportedMediaType is being called when the request body is sent in a format the resource does not accept
+func RenderUnsupportedMediaType(w http.ResponseWriter, r *http.Request, mediaType string) {
+	RenderErrResponse(
+		w,
+		r,
+		ErrRender(
+			"UnsupportedMediaType",
+			"Content-Type",
+			fmt.Sprintf("The '%s' media type is not supported by this resource.", mediaType),
+			http.StatusUnsupportedMediaType,
+		),
+	)
+}
+
+// RenderRequestEntityTooLarge is being called when the request body is larger than the server accepts
+func RenderRequestEntityTooLarge(w http.ResponseWriter, r *http.Request, limit int) {
+	RenderErrResponse(
+		w,
+		r,
+		ErrRender(
+			"RequestEntityTooLarge",
+			"",
+			localize(w, r).Message("RequestEntityTooLarge", "The request body must not exceed %d bytes.", limit),
+			http.StatusRequestEntityTooLarge,
+		),
+	)
+}
+
+// RenderUnprocessableEntity is being called when the request is well formed but cannot be applied
+func RenderUnprocessableEntity(w http.ResponseWriter, r *http.Request, target, message string) {
+	RenderErrResponse(
+		w,
+		r,
+		ErrRender("UnprocessableEntity", target, message, http.StatusUnprocessableEntity),
+	)
+}
+
+// RenderConflict is being called when the request conflicts with the current state of the resource
+func RenderConflict(w http.ResponseWriter, r *http.Request, target, message string, current interface{}) {
+	RenderErrResponse(
 		w,
 		r,
-		ErrRender("InvalidInput", target, message, http.StatusBadRequest),
+		&ErrResponse{
+			Errors: Error{
+				Code:    "Conflict",
+				Target:  target,
+				Message: message,
+				Details: current,
+			},
+			HTTPStatusCode: http.StatusConflict,
+		},
 	)
 }
 
 // RenderBadGateway is being called when the server receives an invalid response from another server
 func RenderBadGateway(w http.ResponseWriter, r *http.Request, err error) {
-	render.Render(
+	RenderErrResponse(
 		w,
 		r,
 		ErrRender("BadGateway", "", err.Error(), http.StatusBadGateway),
 	)
 }
+
+// RenderServiceUnavailable is being called when a service the request depends on cannot be reached
+func RenderServiceUnavailable(w http.ResponseWriter, r *http.Request, err error) {
+	context.Logger(r.Context()).Error(err)
+
+	w.Header().Set("Retry-After", "5")
+	RenderErrResponse(
+		w,
+		r,
+		ErrRender("Unavailable", "", "The service is temporarily unavailable, please retry later.", http.StatusServiceUnavailable),
+	)
+}
+
+// RenderTimeout is being called when the request could not be answered in time
+func RenderTimeout(w http.ResponseWriter, r *http.Request) {
+	context.Logger(r.Context()).Warn("The request timed out")
+
+	RenderErrResponse(
+		w,
+		r,
+		ErrRender("Timeout", "", "The request could not be completed in time, please retry later.", http.StatusGatewayTimeout),
+	)
+}
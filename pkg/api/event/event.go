@@ -0,0 +1,78 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gregbiv/news-api/pkg/model"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errStreamingUnsupported represents the error when the response writer cannot be flushed
+var errStreamingUnsupported = errors.New("Streaming is not supported by the response writer")
+
+// typeFilter is the set of event types a client is interested in, empty meaning all of them
+type typeFilter map[string]bool
+
+// typesFromRequest reads the comma separated "types" query parameter, it may be repeated
+func typesFromRequest(r *http.Request) typeFilter {
+	filter := typeFilter{}
+	for _, param := range r.URL.Query()["types"] {
+		for _, eventType := range strings.Split(param, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				filter[eventType] = true
+			}
+		}
+	}
+
+	return filter
+}
+
+func (f typeFilter) matches(event *model.Event) bool {
+	return len(f) == 0 || f[event.Type]
+}
+
+func (f typeFilter) list() []string {
+	types := make([]string, 0, len(f))
+	for eventType := range f {
+		types = append(types, eventType)
+	}
+
+	return types
+}
+
+// lastEventID reads the ID of the last event the client received, as sent by EventSource on reconnection.
+// The query parameter serves the clients that cannot set headers.
+func lastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	ID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ID < 0 {
+		return 0, fmt.Errorf("Invalid event ID %s", value)
+	}
+
+	return ID, nil
+}
+
+// writeEvent writes the event in the text/event-stream format. The commit sequence of the event is
+// its stream ID rather than its ID, as the IDs are not handed out in the order the events are committed.
+func writeEvent(w io.Writer, event *model.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data)
+
+	return err
+}
@@ -0,0 +1,94 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypesFromRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It accepts comma separated and repeated types", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/v1/events?types=category.created,%20category.updated&types=category.deleted", nil)
+
+		types := typesFromRequest(r).list()
+		sort.Strings(types)
+
+		assert.Equal(t, []string{"category.created", "category.deleted", "category.updated"}, types)
+	})
+
+	t.Run("It matches every type without filter", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/v1/events", nil)
+
+		assert.True(t, typesFromRequest(r).matches(&model.Event{Type: "category.created"}))
+	})
+
+	t.Run("It matches only the filtered types", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/v1/events?types=category.deleted", nil)
+		filter := typesFromRequest(r)
+
+		assert.True(t, filter.matches(&model.Event{Type: "category.deleted"}))
+		assert.False(t, filter.matches(&model.Event{Type: "category.created"}))
+	})
+}
+
+func TestLastEventID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It prefers the header", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/v1/events?last_event_id=3", nil)
+		r.Header.Set("Last-Event-ID", "42")
+
+		ID, err := lastEventID(r)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), ID)
+	})
+
+	t.Run("It falls back to the query string", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/v1/events?last_event_id=3", nil)
+
+		ID, err := lastEventID(r)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), ID)
+	})
+
+	t.Run("It rejects invalid IDs", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/v1/events", nil)
+		r.Header.Set("Last-Event-ID", "abc")
+
+		_, err := lastEventID(r)
+		assert.Error(t, err)
+	})
+}
+
+func TestWriteEvent(t *testing.T) {
+	t.Parallel()
+
+	event := &model.Event{
+		ID:           7,
+		Sequence:     9,
+		Type:         "category.updated",
+		ResourceType: "category",
+		ResourceID:   "f3c2e6e8-7a4c-4a5b-9b1d-4a2f6c1d8e9a",
+		Payload:      json.RawMessage(`{"name":"sports"}`),
+		CreatedAt:    time.Date(2017, 11, 8, 22, 36, 6, 0, time.UTC),
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, writeEvent(buf, event))
+	assert.Equal(
+		t,
+		"id: 9\nevent: category.updated\n"+
+			`data: {"id":7,"type":"category.updated","resource_type":"category",`+
+			`"resource_id":"f3c2e6e8-7a4c-4a5b-9b1d-4a2f6c1d8e9a","data":{"name":"sports"},"created_at":"2017-11-08T22:36:06Z"}`+
+			"\n\n",
+		buf.String(),
+	)
+}
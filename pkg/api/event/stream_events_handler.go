@@ -0,0 +1,120 @@
+package event
+
+import (
+	"context"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/event"
+	"github.com/gregbiv/news-api/pkg/storage/outbox"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// replayBatch is the amount of events read at once when a client resumes
+	replayBatch = 100
+	// heartbeatInterval keeps idle connections from being closed by proxies
+	heartbeatInterval = 15 * time.Second
+)
+
+type streamEventsHandler struct {
+	broker *event.Broker
+	reader outbox.Reader
+}
+
+// NewStreamEventsHandler init and returns an instance of streamEventsHandler
+func NewStreamEventsHandler(broker *event.Broker, reader outbox.Reader) http.Handler {
+	return &streamEventsHandler{
+		broker: broker,
+		reader: reader,
+	}
+}
+
+// ServeHTTP streams the content changes as Server-Sent Events
+func (h *streamEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		api.RenderInternalServerError(w, r, errStreamingUnsupported)
+		return
+	}
+
+	lastID, err := lastEventID(r)
+	if err != nil {
+		api.RenderInvalidInput(w, r, "Last-Event-ID", "InvalidEventID", err.Error())
+		return
+	}
+
+	types := typesFromRequest(r)
+
+	// Subscribe before replaying, so that no event
+	// is committed unnoticed in between
+	subscription := h.broker.Subscribe()
+	defer h.broker.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	if lastID, err = h.replay(r.Context(), w, lastID, types); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ":\n\n"); err != nil {
+				return
+			}
+		case ev, open := <-subscription.Events():
+			// The client fell behind, closing the stream lets it resume from its last event
+			if !open {
+				return
+			}
+			// Skip what was already replayed. The events are notified in commit order,
+			// so none that follows lastID can have been committed before it.
+			if ev.Sequence <= lastID || !types.matches(ev) {
+				continue
+			}
+			if err := writeEvent(w, ev); err != nil {
+				return
+			}
+			lastID = ev.Sequence
+		}
+		flusher.Flush()
+	}
+}
+
+// replay writes the events committed after the one of lastID and returns the ID of the last written one
+func (h *streamEventsHandler) replay(ctx context.Context, w io.Writer, lastID int64, types typeFilter) (int64, error) {
+	// New clients only receive the events that follow their subscription
+	if lastID == 0 {
+		return 0, nil
+	}
+
+	for {
+		events, err := h.reader.ListSince(ctx, lastID, types.list(), replayBatch)
+		if err != nil {
+			return lastID, err
+		}
+
+		for _, ev := range events {
+			if err := writeEvent(w, ev); err != nil {
+				return lastID, err
+			}
+			lastID = ev.Sequence
+		}
+
+		if len(events) < replayBatch {
+			return lastID, nil
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/gregbiv/news-api/pkg/event"
+	"github.com/gregbiv/news-api/pkg/model"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// Control message types sent by the clients
+	messageSubscribe   = "subscribe"
+	messageUnsubscribe = "unsubscribe"
+
+	// Message types sent to the clients
+	messageSubscribed = "subscribed"
+	messageEvent      = "event"
+	messageError      = "error"
+
+	// writeWait is the time allowed to write a message to the client
+	writeWait = 10 * time.Second
+	// pongWait is the time allowed to read the next pong from the client
+	pongWait = 60 * time.Second
+	// pingInterval has to be shorter than pongWait
+	pingInterval = pongWait * 9 / 10
+	// maxMessageSize is the maximum size of a control message
+	maxMessageSize = 4096
+	// sendBuffer is the amount of messages a connection may lag behind before it is closed
+	sendBuffer = 64
+	// maxSubscriptions is the maximum amount of categories a connection can subscribe to
+	maxSubscriptions = 100
+)
+
+type (
+	// controlMessage is sent by the clients to change their subscriptions
+	controlMessage struct {
+		Type        string   `json:"type"`
+		CategoryIDs []string `json:"category_ids"`
+	}
+
+	// serverMessage is pushed to the clients
+	serverMessage struct {
+		Type        string       `json:"type"`
+		CategoryIDs []string     `json:"category_ids,omitempty"`
+		Event       *model.Event `json:"event,omitempty"`
+		Message     string       `json:"message,omitempty"`
+	}
+
+	// wsClient pushes the events of the categories it subscribed to over a WebSocket connection.
+	// All writes go through the send buffer, so that a single goroutine writes to the connection.
+	wsClient struct {
+		conn         *websocket.Conn
+		subscription *event.Subscription
+		send         chan *serverMessage
+
+		done        chan struct{}
+		closeOnce   sync.Once
+		closeCode   int
+		closeReason string
+
+		mu          sync.RWMutex
+		categoryIDs map[string]bool
+	}
+)
+
+func newWSClient(conn *websocket.Conn, subscription *event.Subscription) *wsClient {
+	return &wsClient{
+		conn:         conn,
+		subscription: subscription,
+		send:         make(chan *serverMessage, sendBuffer),
+		done:         make(chan struct{}),
+		categoryIDs:  make(map[string]bool),
+	}
+}
+
+// close asks the writer to close the connection with the given code, only the first call has an effect
+func (c *wsClient) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.done)
+	})
+}
+
+// enqueue buffers the message, closing the connection of the clients that are not keeping up
+func (c *wsClient) enqueue(message *serverMessage) {
+	select {
+	case c.send <- message:
+	default:
+		c.close(websocket.ClosePolicyViolation, "Send buffer is full")
+	}
+}
+
+// wants tells whether the client subscribed to the category the event is about
+func (c *wsClient) wants(ev *model.Event) bool {
+	if ev.ResourceType != storageCategory.ResourceType {
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.categoryIDs[ev.ResourceID]
+}
+
+// handle applies a control message and acknowledges it with the resulting subscriptions
+func (c *wsClient) handle(data []byte) {
+	message := controlMessage{}
+	if err := json.Unmarshal(data, &message); err != nil {
+		c.enqueue(&serverMessage{Type: messageError, Message: "Invalid control message"})
+		return
+	}
+
+	for _, categoryID := range message.CategoryIDs {
+		if _, err := uuid.FromString(categoryID); err != nil {
+			c.enqueue(&serverMessage{Type: messageError, Message: fmt.Sprintf("Invalid category ID %s", categoryID)})
+			return
+		}
+	}
+
+	c.mu.Lock()
+	switch message.Type {
+	case messageSubscribe:
+		if len(c.categoryIDs)+len(message.CategoryIDs) > maxSubscriptions {
+			c.mu.Unlock()
+			c.enqueue(&serverMessage{
+				Type:    messageError,
+				Message: fmt.Sprintf("A connection can subscribe to at most %d categories", maxSubscriptions),
+			})
+			return
+		}
+		for _, categoryID := range message.CategoryIDs {
+			c.categoryIDs[categoryID] = true
+		}
+	case messageUnsubscribe:
+		for _, categoryID := range message.CategoryIDs {
+			delete(c.categoryIDs, categoryID)
+		}
+	default:
+		c.mu.Unlock()
+		c.enqueue(&serverMessage{Type: messageError, Message: fmt.Sprintf("Unknown message type '%s'", message.Type)})
+		return
+	}
+
+	categoryIDs := make([]string, 0, len(c.categoryIDs))
+	for categoryID := range c.categoryIDs {
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	c.mu.Unlock()
+
+	sort.Strings(categoryIDs)
+	c.enqueue(&serverMessage{Type: messageSubscribed, CategoryIDs: categoryIDs})
+}
+
+// read handles the control messages until the connection is closed
+func (c *wsClient) read() {
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.close(websocket.CloseNormalClosure, "")
+			return
+		}
+
+		c.handle(data)
+	}
+}
+
+// forward buffers the events the client subscribed to
+func (c *wsClient) forward() {
+	for ev := range c.subscription.Events() {
+		if c.wants(ev) {
+			c.enqueue(&serverMessage{Type: messageEvent, Event: ev})
+		}
+	}
+
+	// The broker dropped the subscription because the client fell behind or events were missed
+	c.close(websocket.ClosePolicyViolation, "Events were missed")
+}
+
+// write is the only writer of the connection, it also keeps the connection alive
+func (c *wsClient) write() {
+	ticker := time.NewTicker(pingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(message); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			c.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(c.closeCode, c.closeReason),
+				time.Now().Add(writeWait),
+			)
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package event
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/gregbiv/news-api/pkg/event"
+)
+
+type websocketHandler struct {
+	broker   *event.Broker
+	upgrader websocket.Upgrader
+}
+
+// NewWebSocketHandler init and returns an instance of websocketHandler
+func NewWebSocketHandler(broker *event.Broker) http.Handler {
+	return &websocketHandler{
+		broker: broker,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+	}
+}
+
+// ServeHTTP upgrades the connection and pushes the changes of the categories the client subscribes to
+func (h *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The upgrader responds to the client itself when the upgrade fails
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	subscription := h.broker.Subscribe()
+	defer h.broker.Unsubscribe(subscription)
+
+	client := newWSClient(conn, subscription)
+	go client.forward()
+	go client.write()
+
+	client.read()
+}
@@ -0,0 +1,57 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	sportsID   = "6f0b8a1e-2a53-4b0f-9c43-2f1f0c6a1d11"
+	politicsID = "a1d3b6c2-57e4-4d8e-8c7b-0e9f4b2a3c44"
+)
+
+func TestWSClientHandle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It subscribes and unsubscribes categories", func(t *testing.T) {
+		client := newWSClient(nil, nil)
+
+		client.handle([]byte(`{"type":"subscribe","category_ids":["` + sportsID + `","` + politicsID + `"]}`))
+		assert.Equal(t, &serverMessage{Type: messageSubscribed, CategoryIDs: []string{sportsID, politicsID}}, <-client.send)
+
+		client.handle([]byte(`{"type":"unsubscribe","category_ids":["` + politicsID + `"]}`))
+		assert.Equal(t, &serverMessage{Type: messageSubscribed, CategoryIDs: []string{sportsID}}, <-client.send)
+
+		assert.True(t, client.wants(&model.Event{ResourceType: "category", ResourceID: sportsID}))
+		assert.False(t, client.wants(&model.Event{ResourceType: "category", ResourceID: politicsID}))
+	})
+
+	t.Run("It rejects invalid control messages", func(t *testing.T) {
+		client := newWSClient(nil, nil)
+
+		client.handle([]byte(`{"type":`))
+		client.handle([]byte(`{"type":"subscribe","category_ids":["sports"]}`))
+		client.handle([]byte(`{"type":"publish","category_ids":["` + sportsID + `"]}`))
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, messageError, (<-client.send).Type)
+		}
+		assert.Empty(t, client.categoryIDs)
+	})
+
+	t.Run("It closes the connections that are not keeping up", func(t *testing.T) {
+		client := newWSClient(nil, nil)
+
+		for i := 0; i <= sendBuffer; i++ {
+			client.enqueue(&serverMessage{Type: messageEvent})
+		}
+
+		select {
+		case <-client.done:
+		default:
+			t.Fatal("The client was not closed")
+		}
+	})
+}
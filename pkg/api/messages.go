@@ -0,0 +1,199 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Catalog holds the messages of a language keyed by error code, or by the code of the message when
+// several messages share an error code such as InvalidInput. Messages taking arguments,
+// such as the name of a parameter or the constraint of a validation error, are fmt formats.
+type Catalog map[string]string
+
+// languages are the languages the error messages are translated to, the first one being the fallback
+var languages = []language.Tag{
+	language.English,
+	language.German,
+	language.French,
+}
+
+var languageMatcher = language.NewMatcher(languages)
+
+// catalogs holds the message catalog of every supported language. The English catalog only
+// holds the messages of the api package, the other English messages are the ones given by the
+// callers. The other catalogs must translate every code so that a response is never in mixed languages.
+var catalogs = map[language.Tag]Catalog{
+	language.English: {
+		"InvalidUri":      "The requested URI does not represent any resource on the server.",
+		"MissingUriParam": "The '%s' query parameter is required.",
+	},
+	language.German: {
+		"InvalidUri":      "Der angeforderte URI verweist auf keine Ressource des Servers.",
+		"MissingUriParam": "Der Abfrageparameter '%s' ist erforderlich.",
+		"InvalidInput":    "Die Anfrage ist ungültig.",
+		"Required":        "Das Feld ist erforderlich.",
+		"InvalidType":     "Der Wert muss vom Typ %v sein.",
+		"NotAllowed":      "Der Wert muss einer der folgenden sein: %v.",
+		"TooFewItems":     "Es sind mindestens %v Elemente erforderlich.",
+		"TooManyItems":    "Es sind höchstens %v Elemente erlaubt.",
+		"DuplicateItems":  "Die Elemente müssen eindeutig sein.",
+		"UnknownField":    "Das Feld ist nicht erlaubt.",
+		"TooShort":        "Der Wert muss mindestens %v Zeichen lang sein.",
+		"TooLong":         "Der Wert darf höchstens %v Zeichen lang sein.",
+		"PatternMismatch": "Der Wert muss dem Muster '%v' entsprechen.",
+		"InvalidFormat":   "Der Wert entspricht nicht dem Format '%v'.",
+		"NotMultiple":     "Der Wert muss ein Vielfaches von %v sein.",
+		"TooSmall":        "Der Wert ist zu klein (Minimum: %v).",
+		"TooLarge":        "Der Wert ist zu groß (Maximum: %v).",
+		"Invalid":         "Der Wert ist ungültig.",
+
+		"InvalidBody":           "Der Anfragekörper ist ungültig.",
+		"InvalidSkip":           "Der Abfrageparameter '$skip' muss eine positive ganze Zahl sein.",
+		"InvalidTop":            "Der Abfrageparameter '$top' muss eine positive ganze Zahl sein.",
+		"ClientIDNotAllowed":    "Vom Client vergebene Kennungen sind nicht erlaubt.",
+		"ImmutableCategoryID":   "Das Feld 'category_id' kann nicht geändert werden.",
+		"VersionRequired":       "Das Feld 'version' ist erforderlich.",
+		"InvalidAtomic":         "Der Abfrageparameter 'atomic' muss ein boolescher Wert sein.",
+		"InvalidBatchSize":      "Ein Batch erfordert zwischen 1 und %d Operationen.",
+		"InvalidEventID":        "Die ID des zuletzt empfangenen Ereignisses ist ungültig.",
+		"IdempotencyKeyTooLong": "Der Idempotenzschlüssel ist zu lang.",
+		"InvalidDeliveryStatus": "Der 'status' muss pending, delivered oder dead sein.",
+		"InvalidTargetURL":      "Die 'target_url' muss eine absolute http(s)-URL sein.",
+		"InternalTargetURL":     "Die 'target_url' darf nur zu öffentlichen Adressen aufgelöst werden.",
+		"EventTypesRequired":    "Das Feld 'event_types' erfordert mindestens einen Ereignistyp.",
+		"UnknownEventType":      "Unbekannter Ereignistyp '%s'.",
+		"SecretTooShort":        "Das 'secret' muss mindestens %d Zeichen lang sein.",
+		"RequestEntityTooLarge": "Der Anfragekörper darf höchstens %d Bytes groß sein.",
+	},
+	language.French: {
+		"InvalidUri":      "L'URI demandée ne correspond à aucune ressource du serveur.",
+		"MissingUriParam": "Le paramètre de requête '%s' est obligatoire.",
+		"InvalidInput":    "La requête est invalide.",
+		"Required":        "Ce champ est obligatoire.",
+		"InvalidType":     "La valeur doit être de type %v.",
+		"NotAllowed":      "La valeur doit être l'une des suivantes : %v.",
+		"TooFewItems":     "Au moins %v éléments sont requis.",
+		"TooManyItems":    "Au plus %v éléments sont autorisés.",
+		"DuplicateItems":  "Les éléments doivent être uniques.",
+		"UnknownField":    "Ce champ n'est pas autorisé.",
+		"TooShort":        "La valeur doit comporter au moins %v caractères.",
+		"TooLong":         "La valeur doit comporter au plus %v caractères.",
+		"PatternMismatch": "La valeur doit correspondre au motif '%v'.",
+		"InvalidFormat":   "La valeur ne respecte pas le format '%v'.",
+		"NotMultiple":     "La valeur doit être un multiple de %v.",
+		"TooSmall":        "La valeur est trop petite (minimum : %v).",
+		"TooLarge":        "La valeur est trop grande (maximum : %v).",
+		"Invalid":         "La valeur est invalide.",
+
+		"InvalidBody":           "Le corps de la requête est invalide.",
+		"InvalidSkip":           "Le paramètre de requête '$skip' doit être un entier positif.",
+		"InvalidTop":            "Le paramètre de requête '$top' doit être un entier positif.",
+		"ClientIDNotAllowed":    "Les identifiants fournis par le client ne sont pas autorisés.",
+		"ImmutableCategoryID":   "Le champ 'category_id' ne peut pas être modifié.",
+		"VersionRequired":       "Le champ 'version' est obligatoire.",
+		"InvalidAtomic":         "Le paramètre de requête 'atomic' doit être un booléen.",
+		"InvalidBatchSize":      "Un lot nécessite entre 1 et %d opérations.",
+		"InvalidEventID":        "L'identifiant du dernier événement reçu est invalide.",
+		"IdempotencyKeyTooLong": "La clé d'idempotence est trop longue.",
+		"InvalidDeliveryStatus": "Le 'status' doit être pending, delivered ou dead.",
+		"InvalidTargetURL":      "La 'target_url' doit être une URL http(s) absolue.",
+		"InternalTargetURL":     "La 'target_url' doit se résoudre uniquement en adresses publiques.",
+		"EventTypesRequired":    "Le champ 'event_types' requiert au moins un type d'événement.",
+		"UnknownEventType":      "Type d'événement '%s' inconnu.",
+		"SecretTooShort":        "Le 'secret' doit comporter au moins %d caractères.",
+		"RequestEntityTooLarge": "Le corps de la requête ne doit pas dépasser %d octets.",
+	},
+}
+
+// statusTitles holds the titles of the statuses of the error responses in the languages other than
+// English, which uses the standard status texts. Every language must title the same statuses.
+var statusTitles = map[language.Tag]map[int]string{
+	language.German: {
+		http.StatusBadRequest:            "Ungültige Anfrage",
+		http.StatusUnauthorized:          "Nicht authentifiziert",
+		http.StatusForbidden:             "Verboten",
+		http.StatusNotFound:              "Nicht gefunden",
+		http.StatusConflict:              "Konflikt",
+		http.StatusRequestEntityTooLarge: "Anfragekörper zu groß",
+		http.StatusUnsupportedMediaType:  "Nicht unterstützter Medientyp",
+		http.StatusUnprocessableEntity:   "Nicht verarbeitbare Entität",
+		http.StatusInternalServerError:   "Interner Serverfehler",
+		http.StatusBadGateway:            "Fehlerhaftes Gateway",
+		http.StatusServiceUnavailable:    "Dienst nicht verfügbar",
+		http.StatusGatewayTimeout:        "Gateway-Zeitüberschreitung",
+	},
+	language.French: {
+		http.StatusBadRequest:            "Requête invalide",
+		http.StatusUnauthorized:          "Non authentifié",
+		http.StatusForbidden:             "Interdit",
+		http.StatusNotFound:              "Introuvable",
+		http.StatusConflict:              "Conflit",
+		http.StatusRequestEntityTooLarge: "Corps de requête trop volumineux",
+		http.StatusUnsupportedMediaType:  "Type de média non pris en charge",
+		http.StatusUnprocessableEntity:   "Entité non traitable",
+		http.StatusInternalServerError:   "Erreur interne du serveur",
+		http.StatusBadGateway:            "Mauvaise passerelle",
+		http.StatusServiceUnavailable:    "Service indisponible",
+		http.StatusGatewayTimeout:        "Délai de passerelle dépassé",
+	},
+}
+
+// Message returns the message of the code formatted with the arguments, or the fallback
+// message when the catalog does not translate the code
+func (c Catalog) Message(code, fallback string, args ...interface{}) string {
+	message, ok := c[code]
+	if !ok {
+		return fallback
+	}
+
+	if len(args) == 0 || !strings.Contains(message, "%") {
+		return message
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+
+// statusTitle returns the title of the status in the given language, or the standard
+// status text when the language is not supported or does not title the status
+func statusTitle(lang string, status int) string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return http.StatusText(status)
+	}
+
+	if title, ok := statusTitles[tag][status]; ok {
+		return title
+	}
+
+	return http.StatusText(status)
+}
+
+// PreferredLanguage returns the supported language best matching the Accept-Language header
+// of the request, English being used when none does
+func PreferredLanguage(r *http.Request) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return languages[0]
+	}
+
+	_, index, confidence := languageMatcher.Match(tags...)
+	if confidence == language.No {
+		return languages[0]
+	}
+
+	return languages[index]
+}
+
+// localize returns the catalog of the language preferred by the client and announces
+// the language in the headers of the response
+func localize(w http.ResponseWriter, r *http.Request) Catalog {
+	tag := PreferredLanguage(r)
+
+	w.Header().Set("Content-Language", tag.String())
+	w.Header().Add("Vary", "Accept-Language")
+
+	return catalogs[tag]
+}
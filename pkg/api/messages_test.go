@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestPreferredLanguage(t *testing.T) {
+	t.Parallel()
+
+	preferred := func(acceptLanguage string) language.Tag {
+		r := httptest.NewRequest(http.MethodGet, "/v1/category", nil)
+		if acceptLanguage != "" {
+			r.Header.Set("Accept-Language", acceptLanguage)
+		}
+
+		return PreferredLanguage(r)
+	}
+
+	t.Run("It picks the supported language best matching the header", func(t *testing.T) {
+		assert.Equal(t, language.German, preferred("de-AT, en;q=0.5"))
+		assert.Equal(t, language.French, preferred("it, fr;q=0.8, en;q=0.2"))
+	})
+
+	t.Run("It falls back to English", func(t *testing.T) {
+		assert.Equal(t, language.English, preferred(""))
+		assert.Equal(t, language.English, preferred("ja"))
+		assert.Equal(t, language.English, preferred("not a language;;"))
+	})
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	t.Parallel()
+
+	request := func(acceptLanguage string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/v1/audit", nil)
+		r.Header.Set("Accept-Language", acceptLanguage)
+
+		return r
+	}
+
+	decode := func(w *httptest.ResponseRecorder) Error {
+		var response ErrResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		return response.Errors
+	}
+
+	t.Run("It translates the messages keyed by their code", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		RenderErrMissingURIParam(w, request("de"), "resource")
+
+		assert.Equal(t, "de", w.Header().Get("Content-Language"))
+		assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+		assert.Equal(t, "Der Abfrageparameter 'resource' ist erforderlich.", decode(w).Message)
+	})
+
+	t.Run("It keeps the English messages of the callers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		RenderInvalidInput(w, request("en-GB"), "version", "VersionRequired", "The 'version' field is required.")
+
+		assert.Equal(t, "en", w.Header().Get("Content-Language"))
+		assert.Equal(t, "The 'version' field is required.", decode(w).Message)
+	})
+
+	t.Run("It translates the invalid inputs by the code of their message", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		RenderInvalidInput(w, request("de"), "", "InvalidBatchSize", "A batch requires between 1 and %d operations.", 100)
+
+		assert.Equal(t, "Ein Batch erfordert zwischen 1 und 100 Operationen.", decode(w).Message)
+
+		w = httptest.NewRecorder()
+		RenderInvalidInput(w, request("en"), "", "InvalidBatchSize", "A batch requires between 1 and %d operations.", 100)
+
+		assert.Equal(t, "A batch requires between 1 and 100 operations.", decode(w).Message)
+	})
+
+	t.Run("It names the invalid pagination parameter", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/audit?$top=ten", nil)
+		r.Header.Set("Accept-Language", "fr")
+
+		_, _, err := FetchPagination(r)
+		w := httptest.NewRecorder()
+		RenderInvalidPagination(w, r, err)
+
+		response := decode(w)
+		assert.Equal(t, "$top", response.Target)
+		assert.Equal(t, "Le paramètre de requête '$top' doit être un entier positif.", response.Message)
+	})
+
+	t.Run("It translates the validation errors with their constraint", func(t *testing.T) {
+		validationErrs := []ValidationError{
+			{Pointer: "/name", Keyword: "maxLength", Constraint: 255, Code: "TooLong", Message: "String length must be less than or equal to 255"},
+			{Pointer: "/title", Keyword: "required", Code: "Required", Message: "title is required"},
+		}
+
+		w := httptest.NewRecorder()
+		RenderValidationErrors(w, request("fr-CH"), validationErrs)
+
+		var response struct {
+			Error struct {
+				Message string            `json:"message"`
+				Details []ValidationError `json:"details"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		assert.Equal(t, "fr", w.Header().Get("Content-Language"))
+		assert.Equal(t, "La requête est invalide.", response.Error.Message)
+		if assert.Len(t, response.Error.Details, 2) {
+			assert.Equal(t, "La valeur doit comporter au plus 255 caractères.", response.Error.Details[0].Message)
+			assert.Equal(t, "Ce champ est obligatoire.", response.Error.Details[1].Message)
+		}
+		assert.Equal(t, "String length must be less than or equal to 255", validationErrs[0].Message)
+	})
+
+	t.Run("It titles the problems in the language of their detail", func(t *testing.T) {
+		r := request("de")
+		r.Header.Set("Accept", ProblemMediaType)
+
+		w := httptest.NewRecorder()
+		RenderErrMissingURIParam(w, r, "resource")
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+		assert.Equal(t, "Ungültige Anfrage", problem.Title)
+		assert.Equal(t, "Der Abfrageparameter 'resource' ist erforderlich.", problem.Detail)
+	})
+
+	t.Run("It translates every code in every catalog", func(t *testing.T) {
+		for _, tag := range languages[1:] {
+			for code := range catalogs[language.German] {
+				assert.Contains(t, catalogs[tag], code, "%s misses the %s message", tag, code)
+			}
+			assert.Len(t, catalogs[tag], len(catalogs[language.German]))
+
+			for status := range statusTitles[language.German] {
+				assert.Contains(t, statusTitles[tag], status, "%s misses the title of %d", tag, status)
+			}
+			assert.Len(t, statusTitles[tag], len(statusTitles[language.German]))
+		}
+	})
+}
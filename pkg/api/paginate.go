@@ -6,6 +6,13 @@ import (
 	"strconv"
 )
 
+var (
+	// ErrInvalidSkip is returned when the $skip query parameter is not a positive integer
+	ErrInvalidSkip = errors.New("Invalid $skip query parameter")
+	// ErrInvalidTop is returned when the $top query parameter is not a positive integer
+	ErrInvalidTop = errors.New("Invalid $top query parameter")
+)
+
 // FetchPagination returns pagination vars from request
 func FetchPagination(r *http.Request) (*uint64, *uint64, error) {
 	q := r.URL.Query()
@@ -15,7 +22,7 @@ func FetchPagination(r *http.Request) (*uint64, *uint64, error) {
 	if skipStr != "" {
 		skipInt, err := strconv.ParseUint(skipStr, 10, 64)
 		if err != nil {
-			return nil, nil, errors.New("Invalid $skip query parameter")
+			return nil, nil, ErrInvalidSkip
 		}
 		skip = &skipInt
 	}
@@ -25,10 +32,20 @@ func FetchPagination(r *http.Request) (*uint64, *uint64, error) {
 	if topStr != "" {
 		topInt, err := strconv.ParseUint(topStr, 10, 64)
 		if err != nil {
-			return nil, nil, errors.New("Invalid $top query parameter")
+			return nil, nil, ErrInvalidTop
 		}
 		top = &topInt
 	}
 
 	return skip, top, nil
 }
+
+// RenderInvalidPagination is being called when FetchPagination failed
+func RenderInvalidPagination(w http.ResponseWriter, r *http.Request, err error) {
+	if err == ErrInvalidTop {
+		RenderInvalidInput(w, r, "$top", "InvalidTop", err.Error())
+		return
+	}
+
+	RenderInvalidInput(w, r, "$skip", "InvalidSkip", err.Error())
+}
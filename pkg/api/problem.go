@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/context"
+)
+
+// ProblemMediaType is the media type of the RFC 7807 problem details documents
+const ProblemMediaType = "application/problem+json"
+
+// Problem describes an RFC 7807 problem details document, extended with the fields of the legacy errors
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code"`
+	Target   string            `json:"target,omitempty"`
+	Details  interface{}       `json:"details,omitempty"`
+	Errors   []ValidationError `json:"errors,omitempty"`
+}
+
+// RenderErrResponse renders the error as a problem details document to the clients accepting
+// them, and in the legacy {"error": {...}} shape to the other ones
+func RenderErrResponse(w http.ResponseWriter, r *http.Request, e *ErrResponse) {
+	if !acceptsProblem(r) {
+		render.Render(w, r, legacyErrResponse(e))
+		return
+	}
+
+	body, err := json.Marshal(newProblem(w, r, e))
+	if err != nil {
+		context.Logger(r.Context()).Error(err)
+		render.Render(w, r, legacyErrResponse(e))
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemMediaType)
+	w.WriteHeader(e.HTTPStatusCode)
+	w.Write(body)
+}
+
+// legacyErrResponse returns the error in the legacy shape, which reports the validation errors as its details
+func legacyErrResponse(e *ErrResponse) *ErrResponse {
+	if len(e.Errors.Errors) == 0 {
+		return e
+	}
+
+	legacy := *e
+	legacy.Errors.Details = e.Errors.Errors
+
+	return &legacy
+}
+
+// newProblem describes the error as a problem details document. Problems are told apart by their
+// code rather than by their type, which is left blank as the error codes have no documentation pages.
+// The title is in the language the detail was localized to, if any.
+func newProblem(w http.ResponseWriter, r *http.Request, e *ErrResponse) *Problem {
+	return &Problem{
+		Type:     "about:blank",
+		Title:    statusTitle(w.Header().Get("Content-Language"), e.HTTPStatusCode),
+		Status:   e.HTTPStatusCode,
+		Detail:   e.Errors.Message,
+		Instance: r.URL.RequestURI(),
+		Code:     e.Errors.Code,
+		Target:   e.Errors.Target,
+		Details:  e.Errors.Details,
+		Errors:   e.Errors.Errors,
+	}
+}
+
+// acceptsProblem tells whether the Accept header of the request asks for problem details documents
+func acceptsProblem(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil || mediaType != ProblemMediaType {
+			continue
+		}
+
+		if q, ok := params["q"]; ok {
+			if quality, err := strconv.ParseFloat(q, 64); err == nil && quality == 0 {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderErrResponse(t *testing.T) {
+	t.Parallel()
+
+	render := func(accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, "/v1/category/1?atomic=true", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+
+		w := httptest.NewRecorder()
+		RenderInvalidInput(w, r, "name", "NameRequired", "The 'name' field is required.")
+
+		return w
+	}
+
+	t.Run("It renders the legacy shape by default", func(t *testing.T) {
+		w := render("")
+
+		var response ErrResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, Error{Code: "InvalidInput", Target: "name", Message: "The 'name' field is required."}, response.Errors)
+	})
+
+	t.Run("It renders problem details when the client accepts them", func(t *testing.T) {
+		w := render("application/json;q=0.5, application/problem+json")
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ProblemMediaType, w.Header().Get("Content-Type"))
+		assert.Equal(t, Problem{
+			Type:     "about:blank",
+			Title:    "Bad Request",
+			Status:   http.StatusBadRequest,
+			Detail:   "The 'name' field is required.",
+			Instance: "/v1/category/1?atomic=true",
+			Code:     "InvalidInput",
+			Target:   "name",
+		}, problem)
+	})
+
+	t.Run("It renders the validation errors as the details of the legacy shape only", func(t *testing.T) {
+		validationErrs := []ValidationError{{Pointer: "/name", Keyword: "required", Code: "Required", Message: "name is required"}}
+
+		r := httptest.NewRequest(http.MethodPost, "/v1/category", nil)
+		w := httptest.NewRecorder()
+		RenderValidationErrors(w, r, validationErrs)
+
+		var legacy map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &legacy))
+		assert.Contains(t, legacy["error"], "details")
+		assert.NotContains(t, legacy["error"], "errors")
+
+		r.Header.Set("Accept", ProblemMediaType)
+		w = httptest.NewRecorder()
+		RenderValidationErrors(w, r, validationErrs)
+
+		var problem map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Contains(t, problem, "errors")
+		assert.NotContains(t, problem, "details")
+	})
+
+	t.Run("It renders the legacy shape when the client refuses problem details", func(t *testing.T) {
+		w := render("application/problem+json;q=0, application/json")
+
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Contains(t, w.Body.String(), `"error"`)
+	})
+}
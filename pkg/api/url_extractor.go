@@ -5,6 +5,7 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/satori/go.uuid"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -13,6 +14,8 @@ type (
 	URLExtractor interface {
 		// ExtractUUIDFromRoute extracts the uuid from the url
 		UUIDFromRoute(r *http.Request, routeParam string) (*uuid.UUID, error)
+		// Int64FromRoute extracts a positive number from the url
+		Int64FromRoute(r *http.Request, routeParam string) (int64, error)
 		// ExtractDateFromQuery extracts the date from the request
 		DateFromQuery(r *http.Request) (*time.Time, error)
 		// ExtractDateFromRoute extracts the date from the url
@@ -36,6 +39,17 @@ func (e *urlExtractor) UUIDFromRoute(r *http.Request, routeParam string) (*uuid.
 	return &routeUUID, nil
 }
 
+func (e *urlExtractor) Int64FromRoute(r *http.Request, routeParam string) (int64, error) {
+	numberStr := chi.URLParam(r, routeParam)
+
+	number, err := strconv.ParseInt(numberStr, 10, 64)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("Invalid number %s", numberStr)
+	}
+
+	return number, nil
+}
+
 func (e *urlExtractor) DateFromQuery(r *http.Request) (*time.Time, error) {
 	dateStr := r.URL.Query().Get("date")
 
@@ -63,6 +63,39 @@ func TestUrlExtractor_UUIDFromRoute(t *testing.T) {
 	})
 }
 
+func TestUrlExtractor_Int64FromRoute(t *testing.T) {
+	t.Parallel()
+
+	pattern := "/{revision}"
+	handler := func(actualNumber *int64, err *error) http.HandlerFunc {
+		return func(_ http.ResponseWriter, r *http.Request) {
+			*actualNumber, *err = extractor.Int64FromRoute(r, "revision")
+		}
+	}
+
+	t.Run("It successfully extracts the number", func(t *testing.T) {
+		var actualNumber int64
+		var err error
+
+		executeRequest(t, pattern, "/42", handler(&actualNumber, &err))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), actualNumber)
+	})
+
+	t.Run("It fails on invalid numbers", func(t *testing.T) {
+		for _, invalidNumber := range []string{"0", "-1", "first"} {
+			var actualNumber int64
+			var err error
+
+			executeRequest(t, pattern, "/"+invalidNumber, handler(&actualNumber, &err))
+
+			assert.EqualError(t, err, fmt.Sprintf("Invalid number %s", invalidNumber))
+			assert.Equal(t, int64(0), actualNumber)
+		}
+	})
+}
+
 func TestUrlExtractor_DateFromQuery(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"net/http"
+)
+
+type deleteWebhookHandler struct {
+	deleter      storageWebhook.Deleter
+	urlExtractor api.URLExtractor
+}
+
+// NewDeleteWebhookHandler init and returns an instance of deleteWebhookHandler
+func NewDeleteWebhookHandler(deleter storageWebhook.Deleter, urlExtractor api.URLExtractor) http.Handler {
+	return &deleteWebhookHandler{
+		deleter:      deleter,
+		urlExtractor: urlExtractor,
+	}
+}
+
+func (h *deleteWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := h.urlExtractor.UUIDFromRoute(r, "webhook_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	// The pending deliveries and the delivery log are removed along with the webhook
+	if err := h.deleter.Delete(r.Context(), webhookID.String()); err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	render.NoContent(w, r)
+}
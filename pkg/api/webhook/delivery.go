@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"github.com/gregbiv/news-api/pkg/model"
+	"time"
+)
+
+type (
+	// delivery describes a news-api API webhook delivery
+	delivery struct {
+		DeliveryID    int64     `json:"delivery_id"`
+		WebhookID     string    `json:"webhook_id"`
+		EventID       int64     `json:"event_id"`
+		Status        string    `json:"status"`
+		Attempts      int       `json:"attempts"`
+		NextAttemptAt time.Time `json:"next_attempt_at"`
+		LastError     string    `json:"last_error,omitempty"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+	}
+
+	// deliveryList describes a page of webhook deliveries
+	deliveryList struct {
+		Result []delivery `json:"result"`
+	}
+)
+
+func (d *delivery) fromModel(modelDelivery *model.WebhookDelivery) {
+	d.DeliveryID = modelDelivery.DeliveryID
+	d.WebhookID = modelDelivery.WebhookID
+	d.EventID = modelDelivery.EventID
+	d.Status = modelDelivery.Status
+	d.Attempts = modelDelivery.Attempts
+	d.NextAttemptAt = modelDelivery.NextAttemptAt
+	d.LastError = modelDelivery.LastError
+	d.CreatedAt = modelDelivery.CreatedAt
+	d.UpdatedAt = modelDelivery.UpdatedAt
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"net/http"
+)
+
+type getWebhookHandler struct {
+	getter       storageWebhook.Getter
+	urlExtractor api.URLExtractor
+}
+
+// NewGetWebhookHandler init and returns an instance of getWebhookHandler
+func NewGetWebhookHandler(getter storageWebhook.Getter, urlExtractor api.URLExtractor) http.Handler {
+	return &getWebhookHandler{
+		getter:       getter,
+		urlExtractor: urlExtractor,
+	}
+}
+
+func (h *getWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := h.urlExtractor.UUIDFromRoute(r, "webhook_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	modelWebhook, err := h.getter.GetWebhookByID(r.Context(), webhookID.String())
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := webhook{}
+	if err := response.fromModel(modelWebhook); err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
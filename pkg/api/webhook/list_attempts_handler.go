@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"net/http"
+)
+
+type listAttemptsHandler struct {
+	getter       storageWebhook.Getter
+	lister       storageWebhook.AttemptLister
+	urlExtractor api.URLExtractor
+}
+
+// NewListAttemptsHandler init and returns an instance of listAttemptsHandler
+func NewListAttemptsHandler(
+	getter storageWebhook.Getter,
+	lister storageWebhook.AttemptLister,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &listAttemptsHandler{
+		getter:       getter,
+		lister:       lister,
+		urlExtractor: urlExtractor,
+	}
+}
+
+func (h *listAttemptsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := h.urlExtractor.UUIDFromRoute(r, "webhook_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	skip, top, err := api.FetchPagination(r)
+	if err != nil {
+		api.RenderInvalidPagination(w, r, err)
+		return
+	}
+
+	if _, err := h.getter.GetWebhookByID(r.Context(), webhookID.String()); err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	attempts, err := h.lister.ListAttempts(r.Context(), webhookID.String(), skip, top)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, attemptList{Result: attempts})
+}
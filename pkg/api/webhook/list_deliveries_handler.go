@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/model"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"net/http"
+)
+
+type listDeliveriesHandler struct {
+	lister storageWebhook.DeliveryLister
+}
+
+// NewListDeliveriesHandler init and returns an instance of listDeliveriesHandler
+func NewListDeliveriesHandler(lister storageWebhook.DeliveryLister) http.Handler {
+	return &listDeliveriesHandler{
+		lister: lister,
+	}
+}
+
+func (h *listDeliveriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = model.DeliveryDead
+	case model.DeliveryPending, model.DeliveryDelivered, model.DeliveryDead:
+	default:
+		api.RenderInvalidInput(w, r, "status", "InvalidDeliveryStatus", "The 'status' must be one of pending, delivered or dead.")
+		return
+	}
+
+	skip, top, err := api.FetchPagination(r)
+	if err != nil {
+		api.RenderInvalidPagination(w, r, err)
+		return
+	}
+
+	modelDeliveries, err := h.lister.ListByStatus(r.Context(), status, skip, top)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := deliveryList{Result: make([]delivery, len(modelDeliveries))}
+	for i, modelDelivery := range modelDeliveries {
+		response.Result[i].fromModel(modelDelivery)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"net/http"
+)
+
+type listWebhooksHandler struct {
+	lister storageWebhook.Lister
+}
+
+// NewListWebhooksHandler init and returns an instance of listWebhooksHandler
+func NewListWebhooksHandler(lister storageWebhook.Lister) http.Handler {
+	return &listWebhooksHandler{
+		lister: lister,
+	}
+}
+
+func (h *listWebhooksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	skip, top, err := api.FetchPagination(r)
+	if err != nil {
+		api.RenderInvalidPagination(w, r, err)
+		return
+	}
+
+	modelWebhooks, err := h.lister.List(r.Context(), skip, top)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := webhookList{Result: make([]webhook, len(modelWebhooks))}
+	for i, modelWebhook := range modelWebhooks {
+		if err := response.Result[i].fromModel(modelWebhook); err != nil {
+			api.RenderInternalServerError(w, r, err)
+			return
+		}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/context"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"github.com/satori/go.uuid"
+	"net/http"
+)
+
+type postWebhookHandler struct {
+	storer storageWebhook.Storer
+}
+
+// NewPostWebhookHandler init and returns an instance of postWebhookHandler
+func NewPostWebhookHandler(storer storageWebhook.Storer) http.Handler {
+	return &postWebhookHandler{
+		storer: storer,
+	}
+}
+
+// ServeHTTP processes webhook subscriptions
+func (h *postWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookAPI := webhook{}
+	if err := webhookAPI.fromRequest(r); err != nil {
+		context.Logger(r.Context()).Info(err)
+		api.RenderInvalidInput(w, r, "", "InvalidBody", err.Error())
+		return
+	}
+
+	if err := webhookAPI.validate(true); err != nil {
+		invalid := err.(*invalidField)
+		api.RenderInvalidInput(w, r, invalid.target, invalid.code, invalid.message, invalid.args...)
+		return
+	}
+
+	webhookID := uuid.NewV4()
+	webhookAPI.WebhookID = &webhookID
+
+	modelWebhook := webhookAPI.toModel()
+	if err := h.storer.Store(r.Context(), modelWebhook); err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := webhook{}
+	if err := response.fromModel(modelWebhook); err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/context"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"net/http"
+)
+
+type putWebhookHandler struct {
+	getter       storageWebhook.Getter
+	updater      storageWebhook.Updater
+	urlExtractor api.URLExtractor
+}
+
+// NewPutWebhookHandler init and returns an instance of putWebhookHandler
+func NewPutWebhookHandler(
+	getter storageWebhook.Getter,
+	updater storageWebhook.Updater,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &putWebhookHandler{
+		getter:       getter,
+		updater:      updater,
+		urlExtractor: urlExtractor,
+	}
+}
+
+func (h *putWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := h.urlExtractor.UUIDFromRoute(r, "webhook_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	webhookAPI := webhook{}
+	if err := webhookAPI.fromRequest(r); err != nil {
+		context.Logger(r.Context()).Info(err)
+		api.RenderInvalidInput(w, r, "", "InvalidBody", err.Error())
+		return
+	}
+
+	// An omitted secret keeps the current one, so that clients
+	// which never saw it are able to update the subscription
+	if err := webhookAPI.validate(false); err != nil {
+		invalid := err.(*invalidField)
+		api.RenderInvalidInput(w, r, invalid.target, invalid.code, invalid.message, invalid.args...)
+		return
+	}
+
+	webhookAPI.WebhookID = webhookID
+
+	if err := h.updater.Update(r.Context(), webhookAPI.toModel()); err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	modelWebhook, err := h.getter.GetWebhookByID(r.Context(), webhookID.String())
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	response := webhook{}
+	if err := response.fromModel(modelWebhook); err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"net/http"
+)
+
+type retryDeliveryHandler struct {
+	requeuer     storageWebhook.Requeuer
+	urlExtractor api.URLExtractor
+}
+
+// NewRetryDeliveryHandler init and returns an instance of retryDeliveryHandler
+func NewRetryDeliveryHandler(requeuer storageWebhook.Requeuer, urlExtractor api.URLExtractor) http.Handler {
+	return &retryDeliveryHandler{
+		requeuer:     requeuer,
+		urlExtractor: urlExtractor,
+	}
+}
+
+func (h *retryDeliveryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := h.urlExtractor.Int64FromRoute(r, "delivery_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	// Only dead deliveries can be requeued, the others are still handled by the dispatcher
+	err = h.requeuer.Requeue(r.Context(), deliveryID)
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusAccepted)
+	render.NoContent(w, r)
+}
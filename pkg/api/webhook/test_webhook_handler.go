@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/context"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	webhookDispatch "github.com/gregbiv/news-api/pkg/webhook"
+	"net/http"
+)
+
+type testWebhookHandler struct {
+	getter       storageWebhook.Getter
+	recorder     storageWebhook.AttemptRecorder
+	sender       webhookDispatch.Sender
+	urlExtractor api.URLExtractor
+}
+
+// NewTestWebhookHandler init and returns an instance of testWebhookHandler
+func NewTestWebhookHandler(
+	getter storageWebhook.Getter,
+	recorder storageWebhook.AttemptRecorder,
+	sender webhookDispatch.Sender,
+	urlExtractor api.URLExtractor,
+) http.Handler {
+	return &testWebhookHandler{
+		getter:       getter,
+		recorder:     recorder,
+		sender:       sender,
+		urlExtractor: urlExtractor,
+	}
+}
+
+// ServeHTTP sends a ping event to the subscriber and responds with the outcome
+func (h *testWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := h.urlExtractor.UUIDFromRoute(r, "webhook_id")
+	if err != nil {
+		api.NotFound(w, r)
+		return
+	}
+
+	modelWebhook, err := h.getter.GetWebhookByID(r.Context(), webhookID.String())
+	if err != nil {
+		api.RenderError(w, r, err)
+		return
+	}
+
+	ping, err := webhookDispatch.NewPing(modelWebhook)
+	if err != nil {
+		api.RenderInternalServerError(w, r, err)
+		return
+	}
+
+	attempt := h.sender.Send(ping).Attempt(ping)
+	if err := h.recorder.RecordAttempt(r.Context(), attempt); err != nil {
+		context.Logger(r.Context()).Errorf("Recording the webhook attempt failed: %+v", err)
+	}
+
+	// The ping itself succeeded even if the subscriber did not
+	// acknowledge it, the outcome is described by the attempt
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, attempt)
+}
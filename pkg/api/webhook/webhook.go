@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gregbiv/news-api/pkg/model"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	webhookDispatch "github.com/gregbiv/news-api/pkg/webhook"
+	"github.com/satori/go.uuid"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// minSecretLength is the minimum length of the secret used to sign the deliveries
+const minSecretLength = 16
+
+// ErrInvalidBody represents the error when the request body is invalid.
+var ErrInvalidBody = errors.New("Invalid request body provided")
+
+// eventTypes lists the event types a webhook can subscribe to
+var eventTypes = map[string]bool{
+	storageCategory.EventCreated: true,
+	storageCategory.EventUpdated: true,
+	storageCategory.EventDeleted: true,
+}
+
+type (
+	// webhook describes a news-api API webhook subscription,
+	// the secret is accepted but never rendered back
+	webhook struct {
+		WebhookID  *uuid.UUID `json:"webhook_id"`
+		TargetURL  string     `json:"target_url"`
+		EventTypes []string   `json:"event_types"`
+		Secret     string     `json:"secret,omitempty"`
+		Active     *bool      `json:"active"`
+		CreatedAt  *time.Time `json:"created_at,omitempty"`
+	}
+
+	// webhookList describes a page of webhooks
+	webhookList struct {
+		Result []webhook `json:"result"`
+	}
+
+	// attemptList describes a page of the delivery log of a webhook
+	attemptList struct {
+		Result []*model.WebhookAttempt `json:"result"`
+	}
+
+	// invalidField describes which field of the webhook is invalid and why,
+	// the message being the English format of the catalog message of the code
+	invalidField struct {
+		target  string
+		code    string
+		message string
+		args    []interface{}
+	}
+)
+
+func (e *invalidField) Error() string {
+	return fmt.Sprintf(e.message, e.args...)
+}
+
+func (wh *webhook) fromModel(modelWebhook *model.Webhook) error {
+	id, err := uuid.FromString(modelWebhook.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	active := modelWebhook.Active
+	createdAt := modelWebhook.CreatedAt
+
+	wh.WebhookID = &id
+	wh.TargetURL = modelWebhook.TargetURL
+	wh.EventTypes = modelWebhook.EventTypes
+	wh.Active = &active
+	wh.CreatedAt = &createdAt
+
+	return nil
+}
+
+func (wh *webhook) toModel() *model.Webhook {
+	modelWebhook := &model.Webhook{
+		WebhookID:  wh.WebhookID.String(),
+		TargetURL:  wh.TargetURL,
+		EventTypes: wh.EventTypes,
+		Secret:     wh.Secret,
+		Active:     true,
+	}
+
+	if wh.Active != nil {
+		modelWebhook.Active = *wh.Active
+	}
+
+	return modelWebhook
+}
+
+func (wh *webhook) fromRequest(r *http.Request) error {
+	defer r.Body.Close()
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return ErrInvalidBody
+	}
+
+	if err := json.Unmarshal(b, wh); err != nil {
+		return ErrInvalidBody
+	}
+
+	return nil
+}
+
+// validate checks the subscription, the secret is only mandatory when the webhook is created
+func (wh *webhook) validate(requireSecret bool) error {
+	target, err := url.Parse(wh.TargetURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return &invalidField{"target_url", "InvalidTargetURL", "The 'target_url' must be an absolute http(s) URL.", nil}
+	}
+
+	if err := webhookDispatch.CheckTarget(target.Hostname()); err != nil {
+		return &invalidField{"target_url", "InternalTargetURL", "The 'target_url' must resolve to public addresses only.", nil}
+	}
+
+	if len(wh.EventTypes) == 0 {
+		return &invalidField{"event_types", "EventTypesRequired", "The 'event_types' field requires at least one event type.", nil}
+	}
+
+	for _, eventType := range wh.EventTypes {
+		if !eventTypes[eventType] {
+			return &invalidField{"event_types", "UnknownEventType", "Unknown event type '%s'.", []interface{}{eventType}}
+		}
+	}
+
+	if (requireSecret || wh.Secret != "") && len(wh.Secret) < minSecretLength {
+		return &invalidField{"secret", "SecretTooShort", "The 'secret' must be at least %d characters long.", []interface{}{minSecretLength}}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() webhook {
+		return webhook{
+			TargetURL:  "https://203.0.113.10/hooks/news",
+			EventTypes: []string{"category.created", "category.deleted"},
+			Secret:     "0123456789abcdef",
+		}
+	}
+
+	t.Run("It accepts a valid subscription", func(t *testing.T) {
+		wh := valid()
+		assert.NoError(t, wh.validate(true))
+	})
+
+	tests := []struct {
+		name          string
+		modify        func(wh *webhook)
+		requireSecret bool
+		target        string
+	}{
+		{"It rejects relative URLs", func(wh *webhook) { wh.TargetURL = "/hooks" }, true, "target_url"},
+		{"It rejects non http URLs", func(wh *webhook) { wh.TargetURL = "ftp://example.com" }, true, "target_url"},
+		{"It rejects loopback targets", func(wh *webhook) { wh.TargetURL = "http://localhost:8090/v1/admin" }, true, "target_url"},
+		{"It rejects private targets", func(wh *webhook) { wh.TargetURL = "http://10.0.0.12/hooks" }, true, "target_url"},
+		{"It rejects link-local targets", func(wh *webhook) { wh.TargetURL = "http://169.254.169.254/latest/meta-data" }, true, "target_url"},
+		{"It rejects IPv6 loopback targets", func(wh *webhook) { wh.TargetURL = "http://[::1]/hooks" }, true, "target_url"},
+		{"It requires an event type", func(wh *webhook) { wh.EventTypes = nil }, true, "event_types"},
+		{"It rejects unknown event types", func(wh *webhook) { wh.EventTypes = []string{"news.created"} }, true, "event_types"},
+		{"It requires a secret on creation", func(wh *webhook) { wh.Secret = "" }, true, "secret"},
+		{"It rejects short secrets", func(wh *webhook) { wh.Secret = "short" }, false, "secret"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			wh := valid()
+			test.modify(&wh)
+
+			err := wh.validate(test.requireSecret)
+			if assert.Error(t, err) {
+				assert.Equal(t, test.target, err.(*invalidField).target)
+			}
+		})
+	}
+
+	t.Run("It keeps the secret optional on update", func(t *testing.T) {
+		wh := valid()
+		wh.Secret = ""
+		assert.NoError(t, wh.validate(false))
+	})
+}
@@ -0,0 +1,99 @@
+package command
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/gregbiv/news-api/pkg/database"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"github.com/gregbiv/news-api/pkg/webhook"
+	log "github.com/sirupsen/logrus"
+)
+
+// DispatchCommand is responsible for delivering the outbox events to the subscribed webhooks
+type DispatchCommand struct {
+	Meta
+}
+
+// Run is responsible for running the webhook dispatcher until it is interrupted
+func (c *DispatchCommand) Run(args []string) int {
+	var once bool
+
+	flags := c.FlagSet("dispatch")
+	flags.BoolVar(&once, "once", false, "")
+	flags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	db, err := database.Open(c.Config.Database)
+	if err != nil {
+		log.Errorf("Postgres Connection failed: %+v", err)
+		return 1
+	}
+	defer db.Close()
+
+	dispatcher := webhook.NewDispatcher(
+		storageWebhook.NewScheduler(db),
+		storageWebhook.NewClaimer(db),
+		storageWebhook.NewResolver(db),
+		storageWebhook.NewAttemptRecorder(db),
+		webhook.NewSender(c.Config.Webhook.Timeout),
+		webhook.Options{
+			PollInterval: c.Config.Webhook.PollInterval,
+			BatchSize:    c.Config.Webhook.BatchSize,
+			Timeout:      c.Config.Webhook.Timeout,
+			MaxAttempts:  c.Config.Webhook.MaxAttempts,
+		},
+	)
+
+	if once {
+		if err := dispatcher.Dispatch(context.Background()); err != nil {
+			log.Errorf("Dispatching webhooks failed: %+v", err)
+			return 1
+		}
+
+		return 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	dispatcher.Run(ctx)
+
+	return 0
+}
+
+// Help outputs a helper text for the command
+func (*DispatchCommand) Help() string {
+	helpText := `
+Usage: news-api dispatch [options]
+
+  Deliver the resource events to the subscribed webhooks. Failed deliveries
+  are retried with an exponential backoff until WEBHOOK_MAX_ATTEMPTS is
+  reached, after which they are moved to the dead letters.
+
+Options:
+
+  -once
+    Dispatch a single batch and exit instead of polling.
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis outputs to the console the synopsis of the command
+func (c *DispatchCommand) Synopsis() string {
+	return "Deliver resource events to the subscribed webhooks"
+}
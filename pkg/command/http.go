@@ -1,14 +1,23 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-chi/chi"
 	chiMiddleware "github.com/go-chi/chi/middleware"
 	"github.com/gregbiv/news-api/pkg/api"
 	"github.com/gregbiv/news-api/pkg/api/docs"
+	"github.com/gregbiv/news-api/pkg/command/migration"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/event"
+	"github.com/gregbiv/news-api/pkg/identifier"
 	"github.com/gregbiv/news-api/pkg/middleware"
 	"github.com/gregbiv/news-api/pkg/routes"
-	"github.com/jmoiron/sqlx"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/gregbiv/news-api/pkg/storage/category/memory"
+	"github.com/gregbiv/news-api/pkg/storage/idempotency"
+	"github.com/gregbiv/news-api/pkg/storage/outbox"
+	"github.com/gregbiv/news-api/pkg/webhook"
 	"github.com/pressly/lg"
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
@@ -29,25 +38,24 @@ func (c *HTTPCommand) Run(args []string) int {
 		return 1
 	}
 
-	router := chi.NewRouter()
-
-	urlExtractor := api.NewURLExtractor()
-
-	// Setup handler dependencies
-	db, err := sqlx.Open("postgres", c.Config.Database.PostgresDB.DSN)
+	generator, err := identifier.NewGenerator(c.Config.Identifier.Strategy)
 	if err != nil {
-		log.Fatalf("Postgres Connection failed: %+v", err)
+		log.Fatal(err)
 	}
+	assigner := identifier.NewAssigner(generator, c.Config.Identifier.AllowClientIDs)
 
 	// Set package level configurations
 	middleware.Debug = c.Config.Debug
 
+	router := chi.NewRouter()
+
 	// A good base middleware stack
 	router.Use(
-		chiMiddleware.WithValue(middleware.DatabaseConnection, db),
 		chiMiddleware.WithValue("app.config", c.Config),
+		chiMiddleware.RequestID,
 		chiMiddleware.Recoverer,
 		lg.RequestLogger(logrus.StandardLogger()),
+		middleware.Authenticate(c.Config.Auth.AdminToken, c.Config.Auth.Tokens),
 	)
 
 	// HelloWorld
@@ -61,15 +69,84 @@ func (c *HTTPCommand) Run(args []string) int {
 	router.Route("/docs", docs.Docs)
 
 	// Version 1
-	router.Route("/v1", func(r chi.Router) {
-		r.Route("/category", routes.RouteCategory(urlExtractor, db))
-	})
+	switch c.Config.Storage.Driver {
+	case "database":
+		router.Route("/v1", c.routeDatabase(assigner))
+	case "memory":
+		log.Warn("Categories are stored in memory and lost on exit, the other resources are disabled")
+		router.Route("/v1", func(r chi.Router) {
+			r.Route("/category", routes.RouteCategory(api.NewURLExtractor(), memory.NewStorages(), assigner))
+		})
+	default:
+		log.Fatalf("Unknown storage driver %s", c.Config.Storage.Driver)
+	}
 
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", c.Config.Port), router))
 
 	return 0
 }
 
+// routeDatabase registers the routes of the resources stored in the database
+func (c *HTTPCommand) routeDatabase(assigner *identifier.Assigner) func(r chi.Router) {
+	if c.Config.Migration.Auto {
+		err := migration.AutoMigrate(context.Background(), c.Config.Database, c.Config.Migration.Dir)
+		if err != nil {
+			log.Fatalf("Database migration failed: %+v", err)
+		}
+	}
+
+	urlExtractor := api.NewURLExtractor()
+
+	// Setup handler dependencies
+	cluster, err := database.OpenCluster(c.Config.Database)
+	if err != nil {
+		log.Fatalf("Postgres Connection failed: %+v", err)
+	}
+	go cluster.Run(context.Background(), c.Config.Database.ReplicaHealthInterval)
+
+	db := cluster.Primary()
+
+	// A single manager runs the transactions of every storage, so that units of work span them
+	txManager := database.NewTxManager(db)
+
+	// The event streams are long-lived, so only the other resources time out
+	queryTimeout := middleware.Timeout(c.Config.Database.QueryTimeout)
+
+	if db.DriverName() == database.SQLite {
+		log.Warn("Only categories and their audit log are served from SQLite, the other resources are disabled")
+		return func(r chi.Router) {
+			r.With(queryTimeout).Route("/category", routes.RouteCategory(urlExtractor, storageCategory.NewStorages(cluster, txManager), assigner))
+			r.With(queryTimeout).Route("/audit", routes.RouteAudit(cluster))
+		}
+	}
+
+	// Forward the changes committed by any instance to the event stream subscribers
+	broker := event.NewBroker(c.Config.Database.PostgresDB.DSN, outbox.NewReader(db))
+	go func() {
+		if err := broker.Run(context.Background()); err != nil {
+			log.Fatalf("Listening to the outbox failed: %+v", err)
+		}
+	}()
+
+	return func(r chi.Router) {
+		r.Use(
+			chiMiddleware.WithValue(middleware.DatabaseConnection, db),
+			middleware.PrimaryReads(c.Config.Database.ReadYourWrites),
+			middleware.Idempotency(idempotency.NewStore(db), c.Config.Idempotency.TTL, c.Config.Idempotency.Lease),
+		)
+
+		r.With(queryTimeout).Route("/category", routes.RouteCategory(urlExtractor, storageCategory.NewStorages(cluster, txManager), assigner))
+		r.With(queryTimeout).Route("/audit", routes.RouteAudit(cluster))
+		authenticateAccessToken := middleware.AuthenticateAccessToken(c.Config.Auth.AdminToken, c.Config.Auth.Tokens)
+		r.Route("/events", routes.RouteEvent(db, broker, authenticateAccessToken))
+		r.Route("/ws", routes.RouteWebSocket(broker, authenticateAccessToken))
+		// Testing a webhook waits for the subscriber on top of querying the database
+		r.With(middleware.Timeout(c.Config.Database.QueryTimeout+c.Config.Webhook.Timeout)).
+			Route("/webhooks", routes.RouteWebhook(urlExtractor, db, webhook.NewSender(c.Config.Webhook.Timeout)))
+		r.With(queryTimeout).Route("/admin", routes.RouteAdmin(urlExtractor, db))
+	}
+}
+
 // Help outputs a helper text for the command
 func (*HTTPCommand) Help() string {
 	helpText := `
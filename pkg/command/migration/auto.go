@@ -0,0 +1,105 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gregbiv/news-api/pkg/config"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattes/migrate"
+	log "github.com/sirupsen/logrus"
+)
+
+// autoMigrateLockID is the key of the advisory lock serializing the automatic
+// migrations. It differs from the key migrate locks with, which is only tried
+// and fails right away when another instance is migrating.
+const autoMigrateLockID = 4735283904
+
+// ErrSchemaAhead is returned when the database was migrated by a newer binary
+var ErrSchemaAhead = errors.New("The database schema is newer than the migrations of this binary")
+
+// AutoMigrate applies the pending migrations before the application starts.
+// Instances starting at the same time wait for each other on an advisory lock,
+// so that only the first one migrates and the others find nothing left to do.
+func AutoMigrate(ctx context.Context, conf config.Database, dir string) error {
+	db, err := openDB(conf)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// A SQLite database belongs to a single instance
+	if db.DriverName() == database.Postgres {
+		unlock, err := lockMigrations(ctx, db)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	sourceDriver, err := Source(dir, db.DriverName())
+	if err != nil {
+		return err
+	}
+	defer sourceDriver.Close()
+
+	versions, err := sourceVersions(sourceDriver)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("No migration found")
+	}
+	latest := versions[len(versions)-1]
+
+	databaseDriver, err := newDatabaseDriver(db)
+	if err != nil {
+		return err
+	}
+
+	migration, err := migrate.NewWithInstance("source", sourceDriver, db.DriverName(), databaseDriver)
+	if err != nil {
+		return err
+	}
+
+	current, dirty, err := migration.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("The database is dirty at version %d, fix it and run migrate force", current)
+	}
+	if current > latest {
+		log.WithFields(log.Fields{"database": current, "binary": latest}).Error(ErrSchemaAhead)
+		return ErrSchemaAhead
+	}
+
+	if err := migration.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	log.WithField("version", latest).Info("Database migrated")
+
+	return nil
+}
+
+// lockMigrations waits for the advisory lock of the automatic migrations and returns the function releasing it
+func lockMigrations(ctx context.Context, db *sqlx.DB) (func(), error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Waiting for the migration lock")
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", autoMigrateLockID); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", autoMigrateLockID)
+		conn.Close()
+	}, nil
+}
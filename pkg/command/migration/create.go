@@ -0,0 +1,96 @@
+package migration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultDir is where migrations are created when no directory is given
+const defaultDir = "resources/migrations"
+
+// versionFormat is the layout of the timestamp used as version of the created migrations
+const versionFormat = "20060102150405"
+
+var nonIdentifier = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateCommand scaffolds a new migration
+type CreateCommand struct {
+	migrator
+}
+
+// Run creates the up and down files of a new migration named after the argument
+func (c *CreateCommand) Run(args []string) int {
+	flags := c.flagSet("migrate create")
+	flags.Usage = func() { c.ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if flags.NArg() != 1 {
+		c.ui.Error(c.Help())
+		return 1
+	}
+
+	dir := c.dir
+	if dir == "" {
+		dir = defaultDir
+	}
+
+	files, err := createMigration(dir, flags.Arg(0), time.Now())
+	if err != nil {
+		c.ui.Error(err.Error())
+		return 1
+	}
+
+	for _, file := range files {
+		c.ui.Output(fmt.Sprintf("Created %s", file))
+	}
+
+	return 0
+}
+
+// createMigration writes empty up and down files named after the time and the name of the migration
+func createMigration(dir, name string, now time.Time) ([]string, error) {
+	identifier := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if identifier == "" {
+		return nil, fmt.Errorf("Invalid migration name %s", name)
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%s_%s", now.UTC().Format(versionFormat), identifier))
+	files := []string{base + ".up.sql", base + ".down.sql"}
+
+	for _, file := range files {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return nil, err
+		}
+		f.Close()
+	}
+
+	return files, nil
+}
+
+// Help outputs a helper text for the command
+func (*CreateCommand) Help() string {
+	helpText := `
+Usage: news-api migrate create [options] NAME
+
+  Create the up and down files of a new migration, versioned by the current time.
+  Files are created in resources/migrations unless -dir or MIGRATION_DIR is set,
+  the binary must be rebuilt to embed them.
+
+Options:
+
+  ` + dirUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis outputs to the console the synopsis of the command
+func (*CreateCommand) Synopsis() string {
+	return "Create a new migration"
+}
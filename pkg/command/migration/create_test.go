@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMigration(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "migrations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2017, 11, 8, 22, 36, 6, 0, time.UTC)
+
+	t.Run("It creates timestamped up and down files", func(t *testing.T) {
+		files, err := createMigration(dir, "Add category slug", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "20171108223606_add_category_slug.up.sql"),
+			filepath.Join(dir, "20171108223606_add_category_slug.down.sql"),
+		}, files)
+
+		for _, file := range files {
+			_, err := os.Stat(file)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("It does not overwrite existing migrations", func(t *testing.T) {
+		_, err := createMigration(dir, "add-category-slug", now)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("It rejects names without any letter or digit", func(t *testing.T) {
+		_, err := createMigration(dir, "--", now)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"strings"
+)
+
+// DownCommand rolls migrations back
+type DownCommand struct {
+	migrator
+}
+
+// Run rolls back the given amount of migrations
+func (c *DownCommand) Run(args []string) int {
+	var steps int
+
+	flags := c.flagSet("migrate down")
+	flags.IntVar(&steps, "steps", 1, "")
+	flags.Usage = func() { c.ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if steps < 1 {
+		c.ui.Error("The -steps option must be at least 1")
+		return 1
+	}
+
+	migration, _, err := c.migrate()
+	if err != nil {
+		c.ui.Error(err.Error())
+		return 1
+	}
+	defer migration.Close()
+
+	return c.reportVersion(migration, migration.Steps(-steps))
+}
+
+// Help outputs a helper text for the command
+func (*DownCommand) Help() string {
+	helpText := `
+Usage: news-api migrate down [options]
+
+  Roll back the most recent migrations
+
+Options:
+
+  -steps=1
+    Amount of migrations to roll back.
+
+  ` + dirUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis outputs to the console the synopsis of the command
+func (*DownCommand) Synopsis() string {
+	return "Roll back the most recent migrations"
+}
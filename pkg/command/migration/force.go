@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ForceCommand sets the version of the database without running any migration
+type ForceCommand struct {
+	migrator
+}
+
+// Run forces the version given as argument, clearing the dirty flag
+func (c *ForceCommand) Run(args []string) int {
+	flags := c.flagSet("migrate force")
+	flags.Usage = func() { c.ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	version, err := versionArg(flags.Args())
+	if err != nil || version < -1 {
+		c.ui.Error(c.Help())
+		return 1
+	}
+
+	migration, _, err := c.migrate()
+	if err != nil {
+		c.ui.Error(err.Error())
+		return 1
+	}
+	defer migration.Close()
+
+	if err := migration.Force(version); err != nil {
+		c.ui.Error(fmt.Sprintf("Forcing the database version failed: %s", err))
+		return 1
+	}
+
+	return c.reportVersion(migration, nil)
+}
+
+// Help outputs a helper text for the command
+func (*ForceCommand) Help() string {
+	helpText := `
+Usage: news-api migrate force [options] VERSION
+
+  Set the database version without running any migration and clear the
+  dirty flag. Use it to recover once a failed migration was fixed by hand,
+  -1 stands for no migration applied.
+
+Options:
+
+  ` + dirUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis outputs to the console the synopsis of the command
+func (*ForceCommand) Synopsis() string {
+	return "Set the database version and clear the dirty flag"
+}
@@ -0,0 +1,53 @@
+package migration
+
+import (
+	"strings"
+)
+
+// GotoCommand migrates the database up or down to a given version
+type GotoCommand struct {
+	migrator
+}
+
+// Run migrates the database to the version given as argument
+func (c *GotoCommand) Run(args []string) int {
+	flags := c.flagSet("migrate goto")
+	flags.Usage = func() { c.ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	version, err := versionArg(flags.Args())
+	if err != nil || version < 1 {
+		c.ui.Error(c.Help())
+		return 1
+	}
+
+	migration, _, err := c.migrate()
+	if err != nil {
+		c.ui.Error(err.Error())
+		return 1
+	}
+	defer migration.Close()
+
+	return c.reportVersion(migration, migration.Migrate(uint(version)))
+}
+
+// Help outputs a helper text for the command
+func (*GotoCommand) Help() string {
+	helpText := `
+Usage: news-api migrate goto [options] VERSION
+
+  Migrate the database up or down to the given version
+
+Options:
+
+  ` + dirUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis outputs to the console the synopsis of the command
+func (*GotoCommand) Synopsis() string {
+	return "Migrate the database to a given version"
+}
@@ -1,106 +1,190 @@
 package migration
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
-
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gregbiv/news-api/pkg/assets/migrations"
+	"github.com/gregbiv/news-api/pkg/config"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/jmoiron/sqlx"
 	"github.com/mattes/migrate"
-	"github.com/mattes/migrate/database"
+	migrateDatabase "github.com/mattes/migrate/database"
+	"github.com/mattes/migrate/database/postgres"
+	"github.com/mattes/migrate/source"
 	"github.com/mattes/migrate/source/file"
-	log "github.com/sirupsen/logrus"
-	// Enables Postgres for migrations
-	_ "github.com/mattes/migrate/database/postgres"
+	bindata "github.com/mattes/migrate/source/go-bindata"
+	"github.com/mitchellh/cli"
 	// Enables stub DB for testing
 	_ "github.com/mattes/migrate/database/stub"
 )
 
-// CreateMigrateCommand knows how to properly create the MigrateCommand
-func CreateMigrateCommand(db database.Driver, dir string, version uint) *MigrateCommand {
-	cmd := &MigrateCommand{db: db}
-	cmd.flags = flag.NewFlagSet("migrate", flag.ExitOnError)
-	cmd.flags.UintVar(&cmd.version, "version", version, "DB Version to migrate to, i.e. 1")
-	cmd.flags.StringVar(&cmd.dir, "dir", dir, "relative or absolute path for migration files source dir")
+// migrator holds what every migrate subcommand needs
+type migrator struct {
+	ui   cli.Ui
+	conf config.Database
+	dir  string
+}
 
-	return cmd
+// CreateMigrateCommands knows how to properly create the migrate subcommands,
+// which only connect to the database when they run
+func CreateMigrateCommands(ui cli.Ui, conf config.Database, dir string) map[string]cli.CommandFactory {
+	base := migrator{ui: ui, conf: conf, dir: dir}
+
+	return map[string]cli.CommandFactory{
+		"migrate up": func() (cli.Command, error) {
+			return &UpCommand{migrator: base}, nil
+		},
+		"migrate down": func() (cli.Command, error) {
+			return &DownCommand{migrator: base}, nil
+		},
+		"migrate goto": func() (cli.Command, error) {
+			return &GotoCommand{migrator: base}, nil
+		},
+		"migrate status": func() (cli.Command, error) {
+			return &StatusCommand{migrator: base}, nil
+		},
+		"migrate force": func() (cli.Command, error) {
+			return &ForceCommand{migrator: base}, nil
+		},
+		"migrate create": func() (cli.Command, error) {
+			return &CreateCommand{migrator: base}, nil
+		},
+	}
 }
 
-// MigrateCommand represents a CLI migrate command
-type MigrateCommand struct {
-	flags *flag.FlagSet
-	db    database.Driver
+// flagSet returns a FlagSet with the -dir flag every subcommand accepts
+func (m *migrator) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(&m.dir, "dir", m.dir, "")
 
-	// cli args
-	version uint
-	dir     string
+	return flags
 }
 
-// Run execute the migration command
-func (migrateCmd *MigrateCommand) Run(args []string) int {
-	if err := migrateCmd.flags.Parse(args); err != nil {
-		log.Error(err)
-		return 1
+// sqliteDir is the directory of the migrations of SQLite databases, within the one of the Postgres migrations
+const sqliteDir = "sqlite"
+
+// Source returns the migrations of the database driver embedded into the binary,
+// or the ones read from disk when a directory is given
+func Source(dir, driverName string) (source.Driver, error) {
+	prefix := ""
+	if driverName == database.SQLite {
+		prefix = sqliteDir + "/"
 	}
 
-	if migrateCmd.dir == "" {
-		log.Errorf("empty option: -dir. args: %v", args)
-		return 1
+	if dir != "" {
+		f := &file.File{}
+		return f.Open("file://" + filepath.Join(dir, prefix))
 	}
 
-	if migrateCmd.version == 0 {
-		log.Errorf("empty option: -version. args: %v", args)
-		return 1
+	var names []string
+	for _, name := range migrations.AssetNames() {
+		if !strings.HasPrefix(name, prefix) || strings.Contains(strings.TrimPrefix(name, prefix), "/") {
+			continue
+		}
+		names = append(names, strings.TrimPrefix(name, prefix))
+	}
+
+	return bindata.WithInstance(bindata.Resource(names, func(name string) ([]byte, error) {
+		return migrations.Asset(prefix + name)
+	}))
+}
+
+// newDatabaseDriver wraps the database into the migrate driver of its dialect
+func newDatabaseDriver(db *sqlx.DB) (migrateDatabase.Driver, error) {
+	if db.DriverName() == database.SQLite {
+		return newSQLiteDriver(db.DB)
+	}
+
+	return postgres.WithInstance(db.DB, &postgres.Config{})
+}
+
+// openDB opens the connection migrations run on. Migrations are not bound by the
+// statement timeout, as altering a large table or waiting for a lock may take a while.
+func openDB(conf config.Database) (*sqlx.DB, error) {
+	conf.StatementTimeout = 0
+	conf.MaxOpenConns = 0
+
+	return database.Open(conf)
+}
+
+// migrate configures the migration of the database from the migration files,
+// closing the returned Migrate closes the database connection as well
+func (m *migrator) migrate() (*migrate.Migrate, source.Driver, error) {
+	db, err := openDB(m.conf)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	f := &file.File{}
-	sourceDriver, err := f.Open("file://" + migrateCmd.dir)
+	sourceDriver, err := Source(m.dir, db.DriverName())
 	if err != nil {
-		log.Error(err)
-		return 1
+		db.Close()
+		return nil, nil, err
 	}
 
-	// Configure migration
-	migration, err := migrate.NewWithInstance(
-		"file",
-		sourceDriver,
-		"database",
-		migrateCmd.db,
-	)
+	databaseDriver, err := newDatabaseDriver(db)
 	if err != nil {
-		log.Error(err)
-		return 1
+		sourceDriver.Close()
+		db.Close()
+		return nil, nil, err
 	}
 
-	// Migrate the system to the correct version
-	err = migration.Migrate(migrateCmd.version)
+	migration, err := migrate.NewWithInstance("source", sourceDriver, db.DriverName(), databaseDriver)
 	if err != nil {
-		if err == migrate.ErrNoChange {
-			log.Infof("Database already at version %d", migrateCmd.version)
-			return 0
-		}
+		sourceDriver.Close()
+		databaseDriver.Close()
+		return nil, nil, err
+	}
 
-		log.Errorf("Database migration error: %s", err)
+	return migration, sourceDriver, nil
+}
+
+// reportVersion outputs the version the database is at after a migration
+func (m *migrator) reportVersion(migration *migrate.Migrate, err error) int {
+	if err != nil && err != migrate.ErrNoChange {
+		m.ui.Error(fmt.Sprintf("Database migration error: %s", err))
+		return 1
+	}
+
+	version, dirty, err := migration.Version()
+	switch {
+	case err == migrate.ErrNilVersion:
+		m.ui.Output("Database has no migration applied")
+	case err != nil:
+		m.ui.Error(err.Error())
 		return 1
+	case dirty:
+		m.ui.Output(fmt.Sprintf("Database at version %d (dirty)", version))
+	default:
+		m.ui.Output(fmt.Sprintf("Database at version %d", version))
 	}
 
-	log.Infof("Migrated database to version %d", migrateCmd.version)
 	return 0
 }
 
-// Help for the migrate command
-func (migrateCmd *MigrateCommand) Help() string {
-	var buf bytes.Buffer
-	fmt.Fprint(&buf, "Migrate database to the given version\n\n")
+// versionArg parses the single version argument of a subcommand
+func versionArg(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("A single version argument is required")
+	}
 
-	migrateCmd.flags.VisitAll(func(f *flag.Flag) {
-		ftype, usage := flag.UnquoteUsage(f)
-		fmt.Fprintf(&buf, "\t-%s %s\n\t\t%s\n", f.Name, ftype, usage)
-	})
+	version, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("Invalid version %s", args[0])
+	}
 
-	return buf.String()
+	return version, nil
 }
 
-// Synopsis of the migrate command
-func (migrateCmd *MigrateCommand) Synopsis() string {
-	return "Migrate database version"
+// dirUsage returns the help string of the -dir flag
+func dirUsage() string {
+	helpText := `
+  -dir=""
+    Relative or absolute path of the migration files to use instead of the
+    ones embedded into the binary, defaults to MIGRATION_DIR.
+`
+	return strings.TrimSpace(helpText)
 }
@@ -0,0 +1,122 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	migrateDatabase "github.com/mattes/migrate/database"
+)
+
+// sqliteVersionTable is the table holding the version SQLite databases are at
+const sqliteVersionTable = "schema_migrations"
+
+// sqliteDriver runs the migrations of SQLite databases, which migrate has no driver for
+type sqliteDriver struct {
+	db *sql.DB
+}
+
+// newSQLiteDriver returns the migrate driver of the SQLite database, creating its version table if needed
+func newSQLiteDriver(db *sql.DB) (migrateDatabase.Driver, error) {
+	query := `CREATE TABLE IF NOT EXISTS ` + sqliteVersionTable + ` (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+	if _, err := db.Exec(query); err != nil {
+		return nil, err
+	}
+
+	return &sqliteDriver{db: db}, nil
+}
+
+func (d *sqliteDriver) Open(url string) (migrateDatabase.Driver, error) {
+	return nil, fmt.Errorf("SQLite migrations require an open database")
+}
+
+func (d *sqliteDriver) Close() error {
+	return d.db.Close()
+}
+
+// Lock does nothing, as a SQLite database belongs to a single instance
+func (d *sqliteDriver) Lock() error {
+	return nil
+}
+
+func (d *sqliteDriver) Unlock() error {
+	return nil
+}
+
+func (d *sqliteDriver) Run(migration io.Reader) error {
+	statements, err := ioutil.ReadAll(migration)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.db.Exec(string(statements))
+
+	return err
+}
+
+func (d *sqliteDriver) SetVersion(version int, dirty bool) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM ` + sqliteVersionTable); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if version >= 0 {
+		query := `INSERT INTO ` + sqliteVersionTable + ` (version, dirty) VALUES (?, ?)`
+		if _, err := tx.Exec(query, version, dirty); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (d *sqliteDriver) Version() (int, bool, error) {
+	var (
+		version int
+		dirty   bool
+	)
+
+	query := `SELECT version, dirty FROM ` + sqliteVersionTable + ` LIMIT 1`
+	err := d.db.QueryRow(query).Scan(&version, &dirty)
+	switch {
+	case err == sql.ErrNoRows:
+		return migrateDatabase.NilVersion, false, nil
+	case err != nil:
+		return 0, false, err
+	}
+
+	return version, dirty, nil
+}
+
+func (d *sqliteDriver) Drop() error {
+	rows, err := d.db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		return err
+	}
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+
+	for _, table := range tables {
+		if _, err := d.db.Exec(`DROP TABLE IF EXISTS "` + table + `"`); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
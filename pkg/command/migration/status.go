@@ -0,0 +1,113 @@
+package migration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattes/migrate"
+	"github.com/mattes/migrate/source"
+)
+
+// StatusCommand outputs the version of the database and the pending migrations
+type StatusCommand struct {
+	migrator
+}
+
+// Run outputs the migration status of the database
+func (c *StatusCommand) Run(args []string) int {
+	flags := c.flagSet("migrate status")
+	flags.Usage = func() { c.ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	migration, sourceDriver, err := c.migrate()
+	if err != nil {
+		c.ui.Error(err.Error())
+		return 1
+	}
+	defer migration.Close()
+
+	current, dirty, err := migration.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		c.ui.Error(err.Error())
+		return 1
+	}
+
+	if err == migrate.ErrNilVersion {
+		c.ui.Output("Version: none")
+	} else {
+		c.ui.Output(fmt.Sprintf("Version: %d", current))
+	}
+	c.ui.Output(fmt.Sprintf("Dirty: %t", dirty))
+
+	versions, err := sourceVersions(sourceDriver)
+	if err != nil {
+		c.ui.Error(err.Error())
+		return 1
+	}
+
+	var pending []string
+	for _, version := range versions {
+		if version <= current {
+			continue
+		}
+
+		_, identifier, err := sourceDriver.ReadUp(version)
+		if err != nil {
+			c.ui.Error(err.Error())
+			return 1
+		}
+		pending = append(pending, fmt.Sprintf("  %d_%s", version, identifier))
+	}
+
+	c.ui.Output(fmt.Sprintf("Pending: %d", len(pending)))
+	for _, line := range pending {
+		c.ui.Output(line)
+	}
+
+	return 0
+}
+
+// sourceVersions lists the versions of the migration files in order
+func sourceVersions(sourceDriver source.Driver) ([]uint, error) {
+	version, err := sourceDriver.First()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	versions := []uint{version}
+	for {
+		version, err = sourceDriver.Next(version)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return versions, nil
+			}
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+}
+
+// Help outputs a helper text for the command
+func (*StatusCommand) Help() string {
+	helpText := `
+Usage: news-api migrate status [options]
+
+  Show the database version, whether it is dirty and the pending migrations
+
+Options:
+
+  ` + dirUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis outputs to the console the synopsis of the command
+func (*StatusCommand) Synopsis() string {
+	return "Show the migration status of the database"
+}
@@ -0,0 +1,45 @@
+package migration
+
+import "strings"
+
+// UpCommand applies all the pending migrations
+type UpCommand struct {
+	migrator
+}
+
+// Run applies all the pending migrations
+func (c *UpCommand) Run(args []string) int {
+	flags := c.flagSet("migrate up")
+	flags.Usage = func() { c.ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	migration, _, err := c.migrate()
+	if err != nil {
+		c.ui.Error(err.Error())
+		return 1
+	}
+	defer migration.Close()
+
+	return c.reportVersion(migration, migration.Up())
+}
+
+// Help outputs a helper text for the command
+func (*UpCommand) Help() string {
+	helpText := `
+Usage: news-api migrate up [options]
+
+  Apply all the pending migrations
+
+Options:
+
+  ` + dirUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis outputs to the console the synopsis of the command
+func (*UpCommand) Synopsis() string {
+	return "Apply all the pending migrations"
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/database"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/gregbiv/news-api/pkg/storage/idempotency"
+	"github.com/gregbiv/news-api/pkg/storage/outbox"
+	log "github.com/sirupsen/logrus"
+)
+
+// PurgeCommand is responsible for permanently removing discarded resources
+type PurgeCommand struct {
+	Meta
+}
+
+// Run is responsible for purging the discarded categories
+func (c *PurgeCommand) Run(args []string) int {
+	var olderThan string
+
+	flags := c.FlagSet("purge")
+	flags.StringVar(&olderThan, "older-than", "30d", "")
+	flags.Usage = func() { c.UI.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	age, err := parseAge(olderThan)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	db, err := database.Open(c.Config.Database)
+	if err != nil {
+		log.Errorf("Postgres Connection failed: %+v", err)
+		return 1
+	}
+	defer db.Close()
+
+	purged, err := storageCategory.NewPurger(database.NewTxManager(db)).Purge(context.Background(), time.Now().Add(-age))
+	if err != nil {
+		log.Errorf("Purging categories failed: %+v", err)
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Purged %d categories discarded more than %s ago", purged, olderThan))
+
+	// SQLite stores neither outbox events nor idempotency keys
+	if db.DriverName() == database.SQLite {
+		return 0
+	}
+
+	pruned, err := outbox.NewPruner(db).Prune(context.Background(), time.Now().Add(-age))
+	if err != nil {
+		log.Errorf("Pruning outbox events failed: %+v", err)
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Deleted %d outbox events created more than %s ago", pruned, olderThan))
+
+	expired, err := idempotency.NewExpirer(db).DeleteExpired(context.Background())
+	if err != nil {
+		log.Errorf("Deleting expired idempotency keys failed: %+v", err)
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Deleted %d expired idempotency keys", expired))
+
+	return 0
+}
+
+// Help outputs a helper text for the command
+func (*PurgeCommand) Help() string {
+	helpText := `
+Usage: news-api purge [options]
+
+  Permanently remove the categories that were discarded a while ago,
+  along with the outbox events handled a while ago and the expired
+  idempotency keys
+
+Options:
+
+  -older-than=30d
+    Only purge resources discarded, or events created, before this age.
+    Accepts days (30d) as well as Go durations (12h, 90m).
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis outputs to the console the synopsis of the command
+func (c *PurgeCommand) Synopsis() string {
+	return "Permanently remove discarded resources"
+}
+
+// parseAge parses a Go duration, additionally accepting a number of days such as "30d"
+func parseAge(age string) (time.Duration, error) {
+	if strings.HasSuffix(age, "d") {
+		days, err := strconv.ParseUint(strings.TrimSuffix(age, "d"), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid age %s", age)
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(age)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("Invalid age %s", age)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It parses days", func(t *testing.T) {
+		age, err := parseAge("30d")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30*24*time.Hour, age)
+	})
+
+	t.Run("It parses durations", func(t *testing.T) {
+		age, err := parseAge("12h30m")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12*time.Hour+30*time.Minute, age)
+	})
+
+	t.Run("It fails on invalid ages", func(t *testing.T) {
+		for _, invalid := range []string{"", "d", "-3d", "thirty days", "-1h"} {
+			_, err := parseAge(invalid)
+
+			assert.EqualError(t, err, "Invalid age "+invalid)
+		}
+	})
+}
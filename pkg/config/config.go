@@ -1,26 +1,86 @@
 package config
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 // Specification represents structured configuration variables
 type Specification struct {
-	Debug     bool   `envconfig:"DEBUG" default:"false"`
-	LogLevel  string `envconfig:"LOG_LEVEL" default:"info"`
-	Port      int    `envconfig:"PORT" default:"8090"`
-	Migration Migration
-	Database  struct {
-		PostgresDB struct {
-			DSN string `envconfig:"DATABASE_DSN"`
-		}
+	Debug       bool   `envconfig:"DEBUG" default:"false"`
+	LogLevel    string `envconfig:"LOG_LEVEL" default:"info"`
+	Port        int    `envconfig:"PORT" default:"8090"`
+	Auth        Auth
+	Migration   Migration
+	Webhook     Webhook
+	Idempotency Idempotency
+	Identifier  Identifier
+	Storage     Storage
+	Database    Database
+}
+
+// Storage config
+type Storage struct {
+	// Driver is either database, to store everything in the Postgres or SQLite database of DATABASE_DSN,
+	// or memory, which only serves the categories and loses them on exit
+	Driver string `envconfig:"STORAGE_DRIVER" default:"database"`
+}
+
+// Database config
+type Database struct {
+	PostgresDB struct {
+		DSN string `envconfig:"DATABASE_DSN"`
 	}
+	MaxOpenConns         int           `envconfig:"DATABASE_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns         int           `envconfig:"DATABASE_MAX_IDLE_CONNS" default:"5"`
+	ConnMaxLifetime      time.Duration `envconfig:"DATABASE_CONN_MAX_LIFETIME" default:"30m"`
+	StatementTimeout     time.Duration `envconfig:"DATABASE_STATEMENT_TIMEOUT" default:"30s"`
+	ConnectRetries       int           `envconfig:"DATABASE_CONNECT_RETRIES" default:"5"`
+	ConnectRetryInterval time.Duration `envconfig:"DATABASE_CONNECT_RETRY_INTERVAL" default:"2s"`
+
+	// Reads are spread over the replicas, as long as they answer the health checks
+	ReplicaDSNs           []string      `envconfig:"DATABASE_REPLICA_DSNS"`
+	ReplicaHealthInterval time.Duration `envconfig:"DATABASE_REPLICA_HEALTH_INTERVAL" default:"5s"`
+	// How long the reads of a client go to the primary after it writes, 0 disables it
+	ReadYourWrites time.Duration `envconfig:"DATABASE_READ_YOUR_WRITES" default:"0s"`
+	// How long a request may query the database before it is answered with a timeout, 0 disables it
+	QueryTimeout time.Duration `envconfig:"DATABASE_QUERY_TIMEOUT" default:"10s"`
+}
+
+// Auth config
+type Auth struct {
+	AdminToken string `envconfig:"AUTH_ADMIN_TOKEN"`
+	// Tokens of the clients, such as the editorial tools, as name:token pairs. The name
+	// of the client is recorded as the actor of the changes it makes.
+	Tokens map[string]string `envconfig:"AUTH_TOKENS"`
+}
+
+// Webhook dispatcher config
+type Webhook struct {
+	PollInterval time.Duration `envconfig:"WEBHOOK_POLL_INTERVAL" default:"1s"`
+	BatchSize    int           `envconfig:"WEBHOOK_BATCH_SIZE" default:"100"`
+	Timeout      time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"10s"`
+	MaxAttempts  int           `envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"10"`
+}
+
+// Idempotency config
+type Idempotency struct {
+	TTL time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`
+	// How long a request holds its key before an identical retry may take it over, it must outlast the requests
+	Lease time.Duration `envconfig:"IDEMPOTENCY_LEASE" default:"1m"`
+}
+
+// Identifier config
+type Identifier struct {
+	Strategy       string `envconfig:"ID_STRATEGY" default:"v4"`
+	AllowClientIDs bool   `envconfig:"ID_ALLOW_CLIENT_IDS" default:"false"`
 }
 
 // Migration config
 type Migration struct {
-	Version uint   `envconfig:"DATABASE_VERSION"`
-	Dir     string `envconfig:"MIGRATION_DIR"`
+	Dir  string `envconfig:"MIGRATION_DIR"`
+	Auto bool   `envconfig:"AUTO_MIGRATE" default:"false"`
 }
 
 // LoadEnv loads config variables into Specification
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strconv"
 
+	"github.com/go-chi/chi/middleware"
 	"github.com/sirupsen/logrus"
 )
 
@@ -12,6 +13,18 @@ type indexContext int
 
 const (
 	traceIDKey indexContext = iota
+	adminKey
+	actorKey
+	claimedActorKey
+	credentialKey
+	primaryKey
+)
+
+const (
+	// SystemActor is the actor of changes that are not made on behalf of a client, i.e. by CLI commands
+	SystemActor = "system"
+	// AnonymousActor is the actor of the requests that do not carry a valid token
+	AnonymousActor = "anonymous"
 )
 
 var (
@@ -32,6 +45,81 @@ func WithTraceID(ctx context.Context, traceID uint64) context.Context {
 	return context.WithValue(ctx, traceIDKey, strconv.FormatUint(traceID, 16))
 }
 
+// WithAdmin returns a copy of the parent context flagged as coming from an administrator.
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey, true)
+}
+
+// IsAdmin tells whether the context was flagged as coming from an administrator.
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey).(bool)
+	return admin
+}
+
+// WithActor returns a copy of the parent context but with the actor performing the request stored on it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the actor stored in the context, falling back to the SystemActor.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+
+	return SystemActor
+}
+
+// WithClaimedActor returns a copy of the parent context but with the actor the client claims to act on behalf of.
+// The claim is not verified, so it must never be used to authorize anything.
+func WithClaimedActor(ctx context.Context, claimedActor string) context.Context {
+	return context.WithValue(ctx, claimedActorKey, claimedActor)
+}
+
+// ClaimedActor returns the actor the client claims to act on behalf of, if any.
+func ClaimedActor(ctx context.Context) string {
+	claimedActor, _ := ctx.Value(claimedActorKey).(string)
+	return claimedActor
+}
+
+// WithCredential returns a copy of the parent context authenticated with the named credential.
+func WithCredential(ctx context.Context, credential string) context.Context {
+	return context.WithValue(ctx, credentialKey, credential)
+}
+
+// Credential returns the name of the credential the context was authenticated with, if any.
+func Credential(ctx context.Context) (string, bool) {
+	credential, ok := ctx.Value(credentialKey).(string)
+	return credential, ok && credential != ""
+}
+
+// WithPrimary returns a copy of the parent context flagged to read from the primary database.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey, true)
+}
+
+// UsePrimary tells whether the reads of the context must go to the primary database.
+func UsePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey).(bool)
+	return primary
+}
+
+// TimedOut tells whether the deadline of the context passed.
+func TimedOut(ctx context.Context) bool {
+	return ctx.Err() == context.DeadlineExceeded
+}
+
+// Detach returns a context carrying the values of the parent context but neither its deadline nor its cancellation,
+// for the work that has to complete even though the request it belongs to timed out or was cancelled.
+func Detach(ctx context.Context) context.Context {
+	return detached{Context: context.Background(), parent: ctx}
+}
+
+// RequestID returns the ID of the request the context belongs to, if any.
+func RequestID(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
+
 // Logger returns an logger with all values from context loaded on it.
 func Logger(ctx context.Context) logrus.FieldLogger {
 	if id, err := traceID(ctx); err == nil {
@@ -41,6 +129,15 @@ func Logger(ctx context.Context) logrus.FieldLogger {
 	return baseLogger
 }
 
+type detached struct {
+	context.Context
+	parent context.Context
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
 func traceID(ctx context.Context) (string, error) {
 	traceID, ok := ctx.Value(traceIDKey).(string)
 	if !ok {
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/config"
+	appContext "github.com/gregbiv/news-api/pkg/context"
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+)
+
+// healthCheckTimeout bounds how long a replica may take to answer a health check
+const healthCheckTimeout = 2 * time.Second
+
+type (
+	// Reader picks the connection pool the reads of a request run on
+	Reader interface {
+		Reader(ctx context.Context) *sqlx.DB
+	}
+
+	// Cluster spreads the reads over the healthy replicas in turn, falling back
+	// to the primary when none is healthy, the context asks for the primary or
+	// holds a transaction begun on it. Anything else must run on the primary.
+	Cluster struct {
+		primary  *sqlx.DB
+		replicas []*replica
+		next     uint32
+	}
+
+	replica struct {
+		db      *sqlx.DB
+		healthy int32
+	}
+)
+
+// OpenCluster opens the primary and the replicas of the configuration. Unlike the
+// primary, replicas that cannot be reached don't prevent the cluster from opening.
+func OpenCluster(conf config.Database) (*Cluster, error) {
+	primary, err := Open(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &Cluster{primary: primary}
+	if primary.DriverName() == SQLite && len(conf.ReplicaDSNs) > 0 {
+		cluster.Close()
+		return nil, fmt.Errorf("SQLite databases cannot have replicas")
+	}
+
+	for _, dsn := range conf.ReplicaDSNs {
+		db, err := openPool(conf, dsn)
+		if err != nil {
+			cluster.Close()
+			return nil, err
+		}
+		cluster.replicas = append(cluster.replicas, &replica{db: db})
+	}
+
+	cluster.checkReplicas(context.Background())
+
+	return cluster, nil
+}
+
+// Primary returns the connection pool of the primary
+func (c *Cluster) Primary() *sqlx.DB {
+	return c.primary
+}
+
+// Reader returns the next healthy replica, or the primary
+func (c *Cluster) Reader(ctx context.Context) *sqlx.DB {
+	if len(c.replicas) == 0 || appContext.UsePrimary(ctx) {
+		return c.primary
+	}
+
+	// The reads of a unit of work see its changes
+	if _, ok := Tx(ctx, c.primary); ok {
+		return c.primary
+	}
+
+	start := atomic.AddUint32(&c.next, 1)
+	for i := range c.replicas {
+		r := c.replicas[(int(start)+i)%len(c.replicas)]
+		if atomic.LoadInt32(&r.healthy) == 1 {
+			return r.db
+		}
+	}
+
+	return c.primary
+}
+
+// Run checks the health of the replicas every interval until the context is cancelled
+func (c *Cluster) Run(ctx context.Context, interval time.Duration) {
+	if len(c.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkReplicas(ctx)
+		}
+	}
+}
+
+// Close closes the primary and the replicas
+func (c *Cluster) Close() error {
+	for _, r := range c.replicas {
+		r.db.Close()
+	}
+
+	return c.primary.Close()
+}
+
+func (c *Cluster) checkReplicas(ctx context.Context) {
+	for i, r := range c.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := r.db.PingContext(pingCtx)
+		cancel()
+
+		healthy := int32(1)
+		if err != nil {
+			healthy = 0
+		}
+
+		if atomic.SwapInt32(&r.healthy, healthy) != healthy {
+			logger := log.WithField("replica", i)
+			if err != nil {
+				logger.Warnf("Database replica is unhealthy, reading from the others: %s", err)
+			} else {
+				logger.Info("Database replica is healthy")
+			}
+		}
+	}
+}
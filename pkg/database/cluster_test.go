@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	appContext "github.com/gregbiv/news-api/pkg/context"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterReader(t *testing.T) {
+	t.Parallel()
+
+	primary := &sqlx.DB{}
+	first := &replica{db: &sqlx.DB{}, healthy: 1}
+	second := &replica{db: &sqlx.DB{}, healthy: 1}
+
+	t.Run("It reads from the primary without replicas", func(t *testing.T) {
+		cluster := &Cluster{primary: primary}
+
+		assert.True(t, primary == cluster.Reader(context.Background()))
+	})
+
+	t.Run("It spreads the reads over the replicas in turn", func(t *testing.T) {
+		cluster := &Cluster{primary: primary, replicas: []*replica{first, second}}
+
+		a := cluster.Reader(context.Background())
+		b := cluster.Reader(context.Background())
+
+		assert.True(t, a != b)
+		assert.True(t, a == first.db || a == second.db)
+		assert.True(t, b == first.db || b == second.db)
+		assert.True(t, a == cluster.Reader(context.Background()))
+	})
+
+	t.Run("It skips the unhealthy replicas", func(t *testing.T) {
+		unhealthy := &replica{db: &sqlx.DB{}}
+		cluster := &Cluster{primary: primary, replicas: []*replica{unhealthy, second}}
+
+		for i := 0; i < 3; i++ {
+			assert.True(t, second.db == cluster.Reader(context.Background()))
+		}
+	})
+
+	t.Run("It falls back to the primary when no replica is healthy", func(t *testing.T) {
+		cluster := &Cluster{primary: primary, replicas: []*replica{{db: &sqlx.DB{}}}}
+
+		assert.True(t, primary == cluster.Reader(context.Background()))
+	})
+
+	t.Run("It reads from the primary when the context asks for it", func(t *testing.T) {
+		cluster := &Cluster{primary: primary, replicas: []*replica{first, second}}
+
+		assert.True(t, primary == cluster.Reader(appContext.WithPrimary(context.Background())))
+	})
+
+	t.Run("It reads from the primary within a transaction begun on it", func(t *testing.T) {
+		cluster := &Cluster{primary: primary, replicas: []*replica{first, second}}
+		ctx := context.WithValue(context.Background(), txKey{}, &ambientTx{tx: &sqlx.Tx{}, db: primary})
+
+		assert.True(t, primary == cluster.Reader(ctx))
+	})
+}
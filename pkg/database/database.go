@@ -0,0 +1,97 @@
+package database
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/config"
+	"github.com/jmoiron/sqlx"
+	// Enables the Postgres driver
+	_ "github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+// Open opens a connection pool configured from conf and waits for the database
+// to accept connections, retrying as many times as configured
+func Open(conf config.Database) (*sqlx.DB, error) {
+	db, err := openPool(conf, conf.PostgresDB.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = db.Ping()
+		if err == nil {
+			return db, nil
+		}
+
+		if attempt >= conf.ConnectRetries {
+			db.Close()
+			return nil, fmt.Errorf("Cannot connect to the database: %s", err)
+		}
+
+		log.WithField("attempt", attempt+1).Warnf("Cannot connect to the database, retrying in %s: %s", conf.ConnectRetryInterval, err)
+		time.Sleep(conf.ConnectRetryInterval)
+	}
+}
+
+// openPool opens a connection pool to the DSN without connecting yet
+func openPool(conf config.Database, dsn string) (*sqlx.DB, error) {
+	driverName, dataSource := Driver(dsn)
+
+	if driverName == SQLite {
+		db, err := sqlx.Open(driverName, dataSource)
+		if err != nil {
+			return nil, err
+		}
+
+		// SQLite only lets a single writer in at once, a single connection
+		// queues the writers instead of making them wait for each other's locks
+		db.SetMaxOpenConns(1)
+
+		return db, nil
+	}
+
+	dataSource, err := withStatementTimeout(dataSource, conf.StatementTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sqlx.Open(driverName, dataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(conf.MaxOpenConns)
+	db.SetMaxIdleConns(conf.MaxIdleConns)
+	db.SetConnMaxLifetime(conf.ConnMaxLifetime)
+
+	return db, nil
+}
+
+// withStatementTimeout adds the statement_timeout run-time parameter to the DSN,
+// which aborts every statement of the connections running longer than timeout
+func withStatementTimeout(dsn string, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return dsn, nil
+	}
+
+	milliseconds := fmt.Sprintf("%d", timeout/time.Millisecond)
+
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return strings.TrimSpace(dsn + " statement_timeout=" + milliseconds), nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("statement_timeout", milliseconds)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
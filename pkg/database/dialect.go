@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// Postgres is the name of the Postgres driver
+	Postgres = "postgres"
+	// SQLite is the name of the SQLite driver
+	SQLite = "sqlite3"
+
+	// sqliteOptions make SQLite wait for the locks of concurrent writers instead of failing
+	// and lock the database as soon as a transaction begins, which stands in for row locks
+	sqliteOptions = "_busy_timeout=5000&_txlock=immediate&_foreign_keys=1"
+)
+
+var (
+	// pqKeyDetail extracts the column from the detail of the Postgres unique violations
+	pqKeyDetail = regexp.MustCompile(`^Key \(([^,)]+)\)=`)
+	// sqliteConstraintFailure extracts the column from the message of the SQLite constraint violations
+	sqliteConstraintFailure = regexp.MustCompile(`constraint failed: (?:\w+\.)?(\w+)`)
+)
+
+// sqliteSchemes are the schemes of the DSNs of SQLite databases, followed by the path of the file
+var sqliteSchemes = []string{"sqlite3://", "sqlite://"}
+
+// Driver returns the name of the driver the DSN is meant for and the data source to open with it
+func Driver(dsn string) (driverName, dataSource string) {
+	for _, scheme := range sqliteSchemes {
+		if !strings.HasPrefix(dsn, scheme) {
+			continue
+		}
+
+		dataSource = strings.TrimPrefix(dsn, scheme)
+		if strings.Contains(dataSource, "?") {
+			return SQLite, dataSource + "&" + sqliteOptions
+		}
+		return SQLite, dataSource + "?" + sqliteOptions
+	}
+
+	return Postgres, dsn
+}
+
+// ForUpdate returns the clause locking the selected rows until the transaction ends. SQLite has no
+// row locks, its transactions lock the whole database as soon as they begin instead.
+func ForUpdate(tx *sqlx.Tx) string {
+	if tx.DriverName() == SQLite {
+		return ""
+	}
+
+	return "FOR UPDATE"
+}
+
+// IsUniqueViolation tells whether the error is caused by inserting a row whose key is already taken
+func IsUniqueViolation(err error) bool {
+	switch err := err.(type) {
+	case *pq.Error:
+		return err.Code.Name() == "unique_violation"
+	case sqlite3.Error:
+		return err.ExtendedCode == sqlite3.ErrConstraintPrimaryKey || err.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+
+	return false
+}
+
+// ConstraintViolation tells whether the error is caused by a row breaking an integrity
+// constraint and returns the column at fault, when the database reports it
+func ConstraintViolation(err error) (column string, ok bool) {
+	switch err := err.(type) {
+	case *pq.Error:
+		if err.Code.Class() != "23" {
+			return "", false
+		}
+		if err.Column != "" {
+			return err.Column, true
+		}
+		// Key (category_id)=(...) already exists.
+		if match := pqKeyDetail.FindStringSubmatch(err.Detail); match != nil {
+			return match[1], true
+		}
+		return "", true
+	case sqlite3.Error:
+		if err.Code != sqlite3.ErrConstraint {
+			return "", false
+		}
+		// UNIQUE constraint failed: category.category_id
+		if match := sqliteConstraintFailure.FindStringSubmatch(err.Error()); match != nil {
+			return match[1], true
+		}
+		return "", true
+	}
+
+	return "", false
+}
+
+// IsUnavailable tells whether the error is caused by the database being unreachable, shutting down or overloaded
+func IsUnavailable(err error) bool {
+	switch err := err.(type) {
+	case *pq.Error:
+		switch err.Code.Class() {
+		case "08", "53":
+			return true
+		}
+		return err.Code == "57P01" || err.Code == "57P02" || err.Code == "57P03"
+	case sqlite3.Error:
+		// The busy timeout expired before the concurrent writers released the database
+		return err.Code == sqlite3.ErrBusy || err.Code == sqlite3.ErrLocked
+	case net.Error:
+		return true
+	}
+
+	return err == driver.ErrBadConn
+}
+
+// IsQueryCanceled tells whether the statement was canceled before it completed, i.e. by the statement timeout
+func IsQueryCanceled(err error) bool {
+	if err, ok := err.(*pq.Error); ok {
+		return err.Code == "57014"
+	}
+
+	return false
+}
+
+// IsSerializationFailure tells whether the transaction failed because of concurrent
+// transactions, in which case it may succeed when it is retried
+func IsSerializationFailure(err error) bool {
+	if err, ok := stacktrace.RootCause(err).(*pq.Error); ok {
+		return err.Code == "40001" || err.Code == "40P01"
+	}
+
+	return false
+}
+
+// InsertID inserts a single row and returns the ID the database generated into the given column
+func InsertID(ctx context.Context, tx *sqlx.Tx, query, column string, args ...interface{}) (int64, error) {
+	if tx.DriverName() == SQLite {
+		result, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
+		if err != nil {
+			return 0, err
+		}
+
+		return result.LastInsertId()
+	}
+
+	var ID int64
+	err := tx.QueryRowxContext(ctx, tx.Rebind(query+" RETURNING "+column), args...).Scan(&ID)
+
+	return ID, err
+}
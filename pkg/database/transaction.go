@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/palantir/stacktrace"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// txRetries is how many times a unit of work is retried after a serialization failure
+	txRetries = 3
+	// txRetryBackoff is the pause before the first retry, doubled before every other one
+	txRetryBackoff = 20 * time.Millisecond
+)
+
+type (
+	// Transactor runs a unit of work spanning several storages within a single transaction
+	Transactor interface {
+		InTx(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error) error
+	}
+
+	// TxManager runs units of work within a transaction it keeps in their context, so that the
+	// storages they call join it and changes spanning several resources are applied atomically
+	TxManager struct {
+		db      *sqlx.DB
+		retries int
+		backoff time.Duration
+	}
+
+	// ambientTx is the transaction of a context along with the database it was begun on
+	ambientTx struct {
+		tx         *sqlx.Tx
+		db         *sqlx.DB
+		savepoints *int
+	}
+
+	txKey struct{}
+)
+
+// NewTxManager inits and returns a TxManager beginning its transactions on the given database
+func NewTxManager(db *sqlx.DB) *TxManager {
+	return &TxManager{db: db, retries: txRetries, backoff: txRetryBackoff}
+}
+
+// InTx runs fn within the transaction of the context, beginning one when there is none yet.
+// Joining a transaction wraps fn in a savepoint, so that a failing fn only rolls back its own
+// changes. The transaction a call began is committed once fn succeeds, and fn runs again from
+// scratch when the transaction failed to serialize or deadlocked. Transactions run at the
+// repeatable read level on Postgres, so that the rows fn read and a concurrent transaction
+// changed before fn wrote them fail to serialize rather than being overwritten.
+func (m *TxManager) InTx(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	if ambient, ok := ctx.Value(txKey{}).(*ambientTx); ok && ambient.db == m.db {
+		return m.inSavepoint(ctx, ambient, fn)
+	}
+
+	backoff := m.backoff
+	for attempt := 0; ; attempt++ {
+		err := m.inNewTx(ctx, fn)
+		if err == nil || attempt == m.retries || !IsSerializationFailure(err) {
+			return err
+		}
+
+		log.Debugf("Retrying the transaction after %s: %s", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Tx returns the transaction of the context begun on the given database, if any
+func Tx(ctx context.Context, db *sqlx.DB) (*sqlx.Tx, bool) {
+	ambient, ok := ctx.Value(txKey{}).(*ambientTx)
+	if !ok || ambient.db != db {
+		return nil, false
+	}
+
+	return ambient.tx, true
+}
+
+// Conn returns the transaction of the context begun on the given database,
+// so that single statements join it, and the database itself otherwise
+func Conn(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := Tx(ctx, db); ok {
+		return tx
+	}
+
+	return db
+}
+
+func (m *TxManager) inNewTx(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	tx, err := m.db.BeginTxx(ctx, m.txOptions())
+	if err != nil {
+		return err
+	}
+
+	savepoints := 0
+	txCtx := context.WithValue(ctx, txKey{}, &ambientTx{tx: tx, db: m.db, savepoints: &savepoints})
+
+	if err := fn(txCtx, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// txOptions returns the options of the transactions, SQLite transactions are serializable
+// anyway and the driver rejects any explicit isolation level
+func (m *TxManager) txOptions() *sql.TxOptions {
+	if m.db.DriverName() == SQLite {
+		return nil
+	}
+
+	return &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+}
+
+func (m *TxManager) inSavepoint(
+	ctx context.Context,
+	ambient *ambientTx,
+	fn func(ctx context.Context, tx *sqlx.Tx) error,
+) error {
+	*ambient.savepoints++
+	savepoint := fmt.Sprintf("sp_%d", *ambient.savepoints)
+
+	if _, err := ambient.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return stacktrace.Propagate(err, "Failed to create savepoint %s", savepoint)
+	}
+
+	if err := fn(ctx, ambient.tx); err != nil {
+		if _, rollbackErr := ambient.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rollbackErr != nil {
+			log.Errorf("Rolling back to savepoint %s failed: %+v", savepoint, rollbackErr)
+		}
+		return err
+	}
+
+	_, err := ambient.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+
+	return stacktrace.Propagate(err, "Failed to release savepoint %s", savepoint)
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager(t *testing.T) {
+	driverName, dataSource := Driver("sqlite3://:memory:")
+	db, err := sqlx.Open(driverName, dataSource)
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE item (name TEXT PRIMARY KEY)`)
+	require.NoError(t, err)
+
+	manager := NewTxManager(db)
+	manager.backoff = 0
+	ctx := context.Background()
+
+	insert := func(name string) func(ctx context.Context, tx *sqlx.Tx) error {
+		return func(ctx context.Context, tx *sqlx.Tx) error {
+			_, err := Conn(ctx, db).ExecContext(ctx, `INSERT INTO item (name) VALUES (?)`, name)
+			return err
+		}
+	}
+
+	items := func() []string {
+		var names []string
+		require.NoError(t, db.Select(&names, `SELECT name FROM item ORDER BY name`))
+		_, err := db.Exec(`DELETE FROM item`)
+		require.NoError(t, err)
+		return names
+	}
+
+	t.Run("It commits the unit of work", func(t *testing.T) {
+		assert.NoError(t, manager.InTx(ctx, insert("a")))
+		assert.Equal(t, []string{"a"}, items())
+	})
+
+	t.Run("It rolls the unit of work back when it fails", func(t *testing.T) {
+		failure := errors.New("failure")
+		err := manager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			require.NoError(t, insert("a")(ctx, tx))
+			return failure
+		})
+
+		assert.Equal(t, failure, err)
+		assert.Empty(t, items())
+	})
+
+	t.Run("It joins the ambient transaction within a savepoint", func(t *testing.T) {
+		err := manager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			ambient, ok := Tx(ctx, db)
+			assert.True(t, ok)
+			assert.Equal(t, tx, ambient)
+
+			require.NoError(t, manager.InTx(ctx, insert("a")))
+			assert.Error(t, manager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+				require.NoError(t, insert("b")(ctx, tx))
+				return insert("a")(ctx, tx)
+			}))
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a"}, items())
+	})
+
+	t.Run("It retries the unit of work after a serialization failure", func(t *testing.T) {
+		attempts := 0
+		err := manager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			attempts++
+			if err := insert("a")(ctx, tx); err != nil {
+				return err
+			}
+			if attempts < 3 {
+				return &pq.Error{Code: "40001"}
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []string{"a"}, items())
+	})
+
+	t.Run("It gives up retrying eventually", func(t *testing.T) {
+		attempts := 0
+		err := manager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			attempts++
+			return &pq.Error{Code: "40P01"}
+		})
+
+		assert.True(t, IsSerializationFailure(err))
+		assert.Equal(t, txRetries+1, attempts)
+	})
+
+	t.Run("It runs the Postgres transactions at the repeatable read level", func(t *testing.T) {
+		assert.Nil(t, manager.txOptions())
+
+		postgres := NewTxManager(sqlx.NewDb(nil, Postgres))
+		assert.Equal(t, &sql.TxOptions{Isolation: sql.LevelRepeatableRead}, postgres.txOptions())
+	})
+}
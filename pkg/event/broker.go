@@ -0,0 +1,190 @@
+package event
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage/outbox"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// subscriptionBuffer is the amount of events a subscriber may lag behind before it is dropped
+	subscriptionBuffer = 64
+	// catchUpBatch is the amount of events read at once when catching up after a reconnection
+	catchUpBatch = 100
+)
+
+type (
+	// Broker fans the events committed by any instance out to the local subscribers.
+	// It listens to the notifications the outbox publishes on commit, so the
+	// subscribers of every instance receive the changes made through any other.
+	Broker struct {
+		dsn    string
+		reader outbox.Reader
+
+		mu          sync.Mutex
+		subscribers map[*Subscription]struct{}
+
+		// lastSequence is the commit sequence of the last event published, only Run uses it
+		lastSequence int64
+	}
+
+	// Subscription receives the events published after it was made
+	Subscription struct {
+		events chan *model.Event
+	}
+)
+
+// NewBroker inits and returns an instance of Broker
+func NewBroker(dsn string, reader outbox.Reader) *Broker {
+	return &Broker{
+		dsn:         dsn,
+		reader:      reader,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Events returns the channel of the subscription. The channel is closed when the subscriber
+// fell too far behind or the broker missed events, it should then catch up from the outbox.
+func (s *Subscription) Events() <-chan *model.Event {
+	return s.events
+}
+
+// Subscribe registers a new subscription
+func (b *Broker) Subscribe() *Subscription {
+	subscription := &Subscription{events: make(chan *model.Event, subscriptionBuffer)}
+
+	b.mu.Lock()
+	b.subscribers[subscription] = struct{}{}
+	b.mu.Unlock()
+
+	return subscription
+}
+
+// Unsubscribe removes the subscription, it is safe to call it more than once
+func (b *Broker) Unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[subscription]; ok {
+		delete(b.subscribers, subscription)
+		close(subscription.events)
+	}
+}
+
+// Publish sends the event to every subscriber, dropping the ones that are not keeping up
+func (b *Broker) Publish(event *model.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscription := range b.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			delete(b.subscribers, subscription)
+			close(subscription.events)
+		}
+	}
+}
+
+// Run listens to the outbox notifications until the context is done
+func (b *Broker) Run(ctx context.Context) error {
+	listener := pq.NewListener(b.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Errorf("Outbox listener failed: %+v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(outbox.Channel); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// A nil notification follows a reconnection, the events committed meanwhile were not notified
+			if notification == nil {
+				b.catchUp(ctx)
+				continue
+			}
+			b.notify(ctx, notification.Extra)
+		case <-ticker.C:
+			go listener.Ping()
+		}
+	}
+}
+
+func (b *Broker) notify(ctx context.Context, payload string) {
+	eventID, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		log.Errorf("Invalid outbox notification %q", payload)
+		return
+	}
+
+	event, err := b.reader.GetEvent(ctx, eventID)
+	if err != nil {
+		log.Errorf("Reading the outbox event %d failed: %+v", eventID, err)
+		return
+	}
+
+	b.publishNext(event)
+}
+
+// catchUp publishes the events committed since the last one published. When none was published
+// yet there is no telling what was missed, the subscriptions are then dropped for the clients to
+// resume from their last event.
+func (b *Broker) catchUp(ctx context.Context) {
+	if b.lastSequence == 0 {
+		b.dropAll()
+		return
+	}
+
+	for {
+		events, err := b.reader.ListSince(ctx, b.lastSequence, nil, catchUpBatch)
+		if err != nil {
+			log.Errorf("Catching up from the outbox event sequence %d failed: %+v", b.lastSequence, err)
+			b.dropAll()
+			return
+		}
+
+		for _, event := range events {
+			b.publishNext(event)
+		}
+
+		if len(events) < catchUpBatch {
+			return
+		}
+	}
+}
+
+// publishNext publishes the event unless it was already published while catching up
+func (b *Broker) publishNext(event *model.Event) {
+	if event.Sequence <= b.lastSequence {
+		return
+	}
+
+	b.lastSequence = event.Sequence
+	b.Publish(event)
+}
+
+// dropAll closes every subscription, as if all the subscribers fell behind
+func (b *Broker) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscription := range b.subscribers {
+		delete(b.subscribers, subscription)
+		close(subscription.events)
+	}
+}
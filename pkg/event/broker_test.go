@@ -0,0 +1,103 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReader struct {
+	events []*model.Event
+}
+
+func (f *fakeReader) GetEvent(ctx context.Context, ID int64) (*model.Event, error) {
+	return nil, nil
+}
+
+func (f *fakeReader) ListSince(ctx context.Context, afterSequence int64, types []string, limit int) ([]*model.Event, error) {
+	events := make([]*model.Event, 0, limit)
+	for _, event := range f.events {
+		if event.Sequence > afterSequence && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func TestBroker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It publishes to every subscriber", func(t *testing.T) {
+		broker := NewBroker("", nil)
+		first, second := broker.Subscribe(), broker.Subscribe()
+
+		broker.Publish(&model.Event{ID: 1})
+
+		assert.Equal(t, int64(1), (<-first.Events()).ID)
+		assert.Equal(t, int64(1), (<-second.Events()).ID)
+	})
+
+	t.Run("It drops the subscribers that fell behind", func(t *testing.T) {
+		broker := NewBroker("", nil)
+		subscription := broker.Subscribe()
+
+		for i := 0; i <= subscriptionBuffer; i++ {
+			broker.Publish(&model.Event{ID: int64(i)})
+		}
+
+		received := 0
+		for range subscription.Events() {
+			received++
+		}
+
+		assert.Equal(t, subscriptionBuffer, received)
+		assert.Empty(t, broker.subscribers)
+	})
+
+	t.Run("It closes the subscription once", func(t *testing.T) {
+		broker := NewBroker("", nil)
+		subscription := broker.Subscribe()
+
+		broker.Unsubscribe(subscription)
+		broker.Unsubscribe(subscription)
+
+		_, open := <-subscription.Events()
+		assert.False(t, open)
+	})
+
+	t.Run("It publishes the events missed during a reconnection", func(t *testing.T) {
+		reader := &fakeReader{}
+		for i := int64(1); i <= catchUpBatch+2; i++ {
+			reader.events = append(reader.events, &model.Event{ID: i, Sequence: i})
+		}
+
+		broker := NewBroker("", reader)
+		broker.publishNext(reader.events[0])
+
+		broker.catchUp(context.Background())
+		assert.Equal(t, int64(catchUpBatch+2), broker.lastSequence)
+
+		reader.events = append(reader.events, &model.Event{ID: catchUpBatch + 3, Sequence: catchUpBatch + 3})
+		subscription := broker.Subscribe()
+
+		broker.catchUp(context.Background())
+		assert.Equal(t, int64(catchUpBatch+3), (<-subscription.Events()).Sequence)
+
+		// The notification of an event published while catching up is not published again
+		broker.publishNext(reader.events[catchUpBatch+2])
+		assert.Len(t, subscription.Events(), 0)
+	})
+
+	t.Run("It drops the subscribers when it cannot tell which events were missed", func(t *testing.T) {
+		broker := NewBroker("", &fakeReader{})
+		subscription := broker.Subscribe()
+
+		broker.catchUp(context.Background())
+
+		_, open := <-subscription.Events()
+		assert.False(t, open)
+	})
+}
@@ -0,0 +1,95 @@
+package identifier
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// StrategyV4 generates random UUIDs
+	StrategyV4 = "v4"
+	// StrategyV7 generates time-ordered UUIDs, which keep the primary key indexes compact
+	StrategyV7 = "v7"
+)
+
+// ErrClientIDNotAllowed is being returned when a client supplies the ID of a resource it creates without being allowed to
+var ErrClientIDNotAllowed = errors.New("Client supplied identifiers are not allowed")
+
+type (
+	// Generator generates the identifiers of new resources
+	Generator interface {
+		NewID() uuid.UUID
+	}
+
+	// Assigner assigns the identifiers of the resources being created
+	Assigner struct {
+		generator      Generator
+		allowClientIDs bool
+	}
+
+	v4Generator struct{}
+
+	v7Generator struct {
+		now func() time.Time
+	}
+)
+
+// NewGenerator returns the generator of the given strategy
+func NewGenerator(strategy string) (Generator, error) {
+	switch strategy {
+	case StrategyV4:
+		return &v4Generator{}, nil
+	case StrategyV7:
+		return &v7Generator{now: time.Now}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown identifier strategy %s", strategy)
+}
+
+// NewAssigner inits and returns an instance of Assigner
+func NewAssigner(generator Generator, allowClientIDs bool) *Assigner {
+	return &Assigner{
+		generator:      generator,
+		allowClientIDs: allowClientIDs,
+	}
+}
+
+// Assign returns the ID of a new resource, which is the one supplied by the client if any and allowed
+func (a *Assigner) Assign(clientID *uuid.UUID) (uuid.UUID, error) {
+	if clientID == nil {
+		return a.generator.NewID(), nil
+	}
+
+	if !a.allowClientIDs {
+		return uuid.Nil, ErrClientIDNotAllowed
+	}
+
+	return *clientID, nil
+}
+
+func (g *v4Generator) NewID() uuid.UUID {
+	return uuid.NewV4()
+}
+
+// NewID returns a UUIDv7: a 48 bits Unix timestamp in milliseconds followed by random bits
+func (g *v7Generator) NewID() uuid.UUID {
+	var id uuid.UUID
+
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic(err)
+	}
+
+	ms := uint64(g.now().UnixNano() / int64(time.Millisecond))
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> uint(40-8*i))
+	}
+
+	id[6] = (id[6] & 0x0f) | 0x70
+	id[8] = (id[8] & 0x3f) | 0x80
+
+	return id
+}
@@ -0,0 +1,67 @@
+package identifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV7Generator(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2017, 11, 8, 22, 36, 6, 956000000, time.UTC)
+	generator := &v7Generator{now: func() time.Time { return now }}
+
+	t.Run("It encodes the timestamp, version and variant", func(t *testing.T) {
+		id := generator.NewID()
+
+		assert.Equal(t, "015f9dc6-b7ac-7", id.String()[:15])
+		assert.Equal(t, uint(7), id.Version())
+		assert.Equal(t, uint(uuid.VariantRFC4122), id.Variant())
+	})
+
+	t.Run("It generates time-ordered identifiers", func(t *testing.T) {
+		first := generator.NewID()
+		now = now.Add(time.Millisecond)
+		second := generator.NewID()
+
+		assert.True(t, first.String() < second.String())
+	})
+}
+
+func TestAssigner(t *testing.T) {
+	t.Parallel()
+
+	generator, err := NewGenerator(StrategyV4)
+	assert.NoError(t, err)
+
+	clientID := uuid.NewV4()
+
+	t.Run("It generates an ID when the client supplied none", func(t *testing.T) {
+		id, err := NewAssigner(generator, false).Assign(nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(4), id.Version())
+	})
+
+	t.Run("It refuses client supplied IDs unless allowed", func(t *testing.T) {
+		_, err := NewAssigner(generator, false).Assign(&clientID)
+
+		assert.Equal(t, ErrClientIDNotAllowed, err)
+	})
+
+	t.Run("It keeps client supplied IDs when allowed", func(t *testing.T) {
+		id, err := NewAssigner(generator, true).Assign(&clientID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, clientID, id)
+	})
+
+	t.Run("It refuses unknown strategies", func(t *testing.T) {
+		_, err := NewGenerator("v1")
+
+		assert.Error(t, err)
+	})
+}
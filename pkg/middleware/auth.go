@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gregbiv/news-api/pkg/api"
+	appContext "github.com/gregbiv/news-api/pkg/context"
+)
+
+const (
+	// ActorHeader is the request header carrying the editor on whose behalf the request is made.
+	// The header is not authenticated, so it is only recorded next to the actor of the credential.
+	ActorHeader = "X-Actor"
+	// AccessTokenParam is the query parameter that may carry the bearer token on the WebSocket route
+	AccessTokenParam = "access_token"
+	// AdminActor is the actor of the requests authenticated with the admin token
+	AdminActor = "admin"
+)
+
+// Authenticate middleware authenticates the requests carrying a bearer token in the Authorization header.
+// The admin token flags the requests as administrator requests, the other tokens are looked up in the tokens
+// of the clients, keyed by their name. The name of the credential is stored in the request context as its actor,
+// and the editor claimed in the ActorHeader is stored next to it. Requests without a valid token are passed
+// through as anonymous, so it is up to the handlers to decide what an anonymous client is allowed to do.
+func Authenticate(adminToken string, tokens map[string]string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := authenticate(r.Context(), bearerToken(r), adminToken, tokens)
+
+			if claimedActor := strings.TrimSpace(r.Header.Get(ActorHeader)); claimedActor != "" {
+				ctx = appContext.WithClaimedActor(ctx, claimedActor)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AuthenticateAccessToken middleware authenticates the anonymous requests carrying a bearer token in
+// the AccessTokenParam. Tokens in URLs end up in access logs and Referer headers, so it is only meant
+// for the WebSocket and event stream routes, as browsers cannot set headers when opening them.
+func AuthenticateAccessToken(adminToken string, tokens map[string]string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get(AccessTokenParam)
+			if _, ok := appContext.Credential(r.Context()); ok || token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(authenticate(r.Context(), token, adminToken, tokens)))
+		})
+	}
+}
+
+// RequireActor middleware rejects the requests that were not authenticated with a credential
+func RequireActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := appContext.Credential(r.Context()); !ok {
+			api.RenderUnauthorized(w, r, "Authenticate with a bearer token.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireAdmin middleware rejects the requests that were not flagged as administrator requests
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !appContext.IsAdmin(r.Context()) {
+			api.RenderForbidden(w, r, "", "Only administrators can access this resource.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearerToken reads the token from the Authorization header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+
+	return ""
+}
+
+// authenticate returns a copy of the context authenticated with the credential of the token, if any
+func authenticate(ctx context.Context, token, adminToken string, tokens map[string]string) context.Context {
+	actor := appContext.AnonymousActor
+	if tokenMatches(token, adminToken) {
+		ctx = appContext.WithAdmin(ctx)
+		actor = AdminActor
+	} else if name, ok := clientName(token, tokens); ok {
+		actor = name
+	}
+
+	if actor != appContext.AnonymousActor {
+		ctx = appContext.WithCredential(ctx, actor)
+	}
+
+	return appContext.WithActor(ctx, actor)
+}
+
+// clientName returns the name of the client the token was issued to. The names of the
+// built-in actors are reserved, so that no client can pass itself off as one of them.
+func clientName(token string, tokens map[string]string) (string, bool) {
+	for name, clientToken := range tokens {
+		switch name {
+		case AdminActor, appContext.AnonymousActor, appContext.SystemActor:
+			continue
+		}
+
+		if tokenMatches(token, clientToken) {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// tokenMatches compares the tokens in constant time, an empty token never matches
+func tokenMatches(token, expected string) bool {
+	return token != "" && expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gregbiv/news-api/pkg/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	type identity struct {
+		actor        string
+		claimedActor string
+		admin        bool
+	}
+
+	var authenticated identity
+	handler := Authenticate("admin-token", map[string]string{"desk": "desk-token", "system": "system-token"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authenticated = identity{
+				actor:        context.Actor(r.Context()),
+				claimedActor: context.ClaimedActor(r.Context()),
+				admin:        context.IsAdmin(r.Context()),
+			}
+		}),
+	)
+
+	authenticate := func(token, claimedActor string) identity {
+		r := httptest.NewRequest(http.MethodPost, "/v1/category", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		if claimedActor != "" {
+			r.Header.Set(ActorHeader, claimedActor)
+		}
+
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		return authenticated
+	}
+
+	t.Run("It takes the actor from the credential", func(t *testing.T) {
+		assert.Equal(t, identity{actor: AdminActor, admin: true}, authenticate("admin-token", ""))
+		assert.Equal(t, identity{actor: "desk"}, authenticate("desk-token", ""))
+	})
+
+	t.Run("It records the claimed actor apart from the authenticated one", func(t *testing.T) {
+		assert.Equal(t, identity{actor: "desk", claimedActor: "jane"}, authenticate("desk-token", "jane"))
+		assert.Equal(t, identity{actor: context.AnonymousActor, claimedActor: "admin"}, authenticate("", "admin"))
+	})
+
+	t.Run("It keeps the requests without a valid token anonymous", func(t *testing.T) {
+		assert.Equal(t, identity{actor: context.AnonymousActor}, authenticate("", ""))
+		assert.Equal(t, identity{actor: context.AnonymousActor}, authenticate("wrong-token", ""))
+	})
+
+	t.Run("It does not let the clients use the names of the built-in actors", func(t *testing.T) {
+		assert.Equal(t, identity{actor: context.AnonymousActor}, authenticate("system-token", ""))
+	})
+}
+
+func TestAccessToken(t *testing.T) {
+	t.Parallel()
+
+	tokens := map[string]string{"mobile": "mobile-token"}
+	required := RequireActor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	t.Run("It ignores the token of the query string outside of the WebSocket route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Authenticate("", tokens)(required).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/events?access_token=mobile-token", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("It authenticates the token of the query string on the WebSocket route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Authenticate("", tokens)(AuthenticateAccessToken("", tokens)(required)).
+			ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/ws?access_token=mobile-token", nil))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("It does not let the claimed actor in", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/ws", nil)
+		r.Header.Set(ActorHeader, "jane")
+
+		w := httptest.NewRecorder()
+		Authenticate("", tokens)(AuthenticateAccessToken("", tokens)(required)).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/context"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage/idempotency"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client generated idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader flags the responses that are replayed for a retried request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize is the size of the largest request body that is buffered to be hashed
+	maxIdempotentBodySize = 1 << 20
+)
+
+// Idempotency middleware makes the POST requests carrying an IdempotencyKeyHeader safe to retry.
+// The response of the first request is stored for the ttl and replayed to the identical retries,
+// while reusing the key for a different request is refused. Server errors are not stored,
+// so the request can be retried once the server recovered. The keys are scoped by the credential
+// of the client, so only the authenticated requests may carry one. A request holds its key for
+// the lease at most, so that the key of a request that crashed can be taken over by a retry.
+func Idempotency(store idempotency.Store, ttl, lease time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The anonymous clients cannot be told apart, they would replay the responses of each other
+			scope, ok := context.Credential(r.Context())
+			if !ok {
+				api.RenderUnauthorized(w, r, "Authenticate with a bearer token to use idempotency keys.")
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				api.RenderInvalidInput(w, r, IdempotencyKeyHeader, "IdempotencyKeyTooLong", "The idempotency key is too long.")
+				return
+			}
+
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+			r.Body.Close()
+			if err != nil {
+				// The reader fails once it read the largest body allowed
+				if len(body) == maxIdempotentBodySize {
+					api.RenderRequestEntityTooLarge(w, r, maxIdempotentBodySize)
+					return
+				}
+				api.RenderInvalidInput(w, r, "", "InvalidBody", "Invalid request body provided")
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			stored, err := store.Begin(r.Context(), scope, key, requestHash(r, body), ttl, lease)
+			switch err {
+			case nil:
+			case idempotency.ErrKeyMismatch:
+				api.RenderUnprocessableEntity(w, r, IdempotencyKeyHeader, err.Error())
+				return
+			case idempotency.ErrInProgress:
+				api.RenderConflict(w, r, IdempotencyKeyHeader, err.Error(), nil)
+				return
+			default:
+				api.RenderError(w, r, err)
+				return
+			}
+
+			if stored != nil {
+				replay(w, stored)
+				return
+			}
+
+			// The key is settled even when the request timed out, otherwise it would stay claimed until it expires
+			settleCtx := context.Detach(r.Context())
+
+			completed := false
+			defer func() {
+				// Give the key up when the request panicked
+				if !completed {
+					if err := store.Release(settleCtx, scope, key); err != nil {
+						context.Logger(r.Context()).Errorf("Releasing the idempotency key failed: %+v", err)
+					}
+				}
+			}()
+
+			buffer := bufferResponse{}
+			next.ServeHTTP(&buffer, r)
+
+			statusCode := buffer.resp
+			if statusCode == 0 {
+				statusCode = http.StatusOK
+			}
+
+			if statusCode < http.StatusInternalServerError {
+				err := store.Complete(settleCtx, scope, key, &model.IdempotentResponse{
+					StatusCode: statusCode,
+					Header:     buffer.Header(),
+					Body:       buffer.Bytes(),
+				})
+				if err != nil {
+					context.Logger(r.Context()).Errorf("Storing the idempotent response failed: %+v", err)
+				} else {
+					completed = true
+				}
+			}
+
+			buffer.Apply(w)
+		})
+	}
+}
+
+// requestHash identifies the request the key is used for
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replay writes the stored response
+func replay(w http.ResponseWriter, response *model.IdempotentResponse) {
+	header := w.Header()
+	for key, values := range response.Header {
+		header[key] = values
+	}
+	header.Set(IdempotentReplayedHeader, "true")
+
+	w.WriteHeader(response.StatusCode)
+	w.Write(response.Body)
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appContext "github.com/gregbiv/news-api/pkg/context"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage/idempotency"
+	"github.com/stretchr/testify/assert"
+)
+
+type storedKey struct {
+	hash     string
+	response *model.IdempotentResponse
+}
+
+type fakeIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]*storedKey
+}
+
+func (f *fakeIdempotencyStore) Begin(ctx context.Context, scope, key, requestHash string, ttl, lease time.Duration) (*model.IdempotentResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.keys[scope+key]
+	if !ok {
+		f.keys[scope+key] = &storedKey{hash: requestHash}
+		return nil, nil
+	}
+	if stored.hash != requestHash {
+		return nil, idempotency.ErrKeyMismatch
+	}
+	if stored.response == nil {
+		return nil, idempotency.ErrInProgress
+	}
+
+	return stored.response, nil
+}
+
+func (f *fakeIdempotencyStore) Complete(ctx context.Context, scope, key string, response *model.IdempotentResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys[scope+key].response = response
+	return nil
+}
+
+func (f *fakeIdempotencyStore) Release(ctx context.Context, scope, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if stored, ok := f.keys[scope+key]; ok && stored.response == nil {
+		delete(f.keys, scope+key)
+	}
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Parallel()
+
+	newHandler := func(statusCode int) (http.Handler, *int) {
+		calls := 0
+		store := &fakeIdempotencyStore{keys: make(map[string]*storedKey)}
+
+		return Idempotency(store, time.Hour, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(statusCode)
+			w.Write(body)
+		})), &calls
+	}
+
+	post := func(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/v1/category/", strings.NewReader(body))
+		r = r.WithContext(appContext.WithCredential(r.Context(), "desk"))
+		if key != "" {
+			r.Header.Set(IdempotencyKeyHeader, key)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	t.Run("It replays the response of identical retries", func(t *testing.T) {
+		handler, calls := newHandler(http.StatusCreated)
+
+		first := post(handler, "abc", `{"name":"sports"}`)
+		retry := post(handler, "abc", `{"name":"sports"}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("It refuses to reuse a key for a different request", func(t *testing.T) {
+		handler, calls := newHandler(http.StatusCreated)
+
+		post(handler, "abc", `{"name":"sports"}`)
+		reused := post(handler, "abc", `{"name":"politics"}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusUnprocessableEntity, reused.Code)
+	})
+
+	t.Run("It refuses the bodies too large to be buffered", func(t *testing.T) {
+		handler, calls := newHandler(http.StatusCreated)
+
+		tooLarge := post(handler, "abc", strings.Repeat("a", maxIdempotentBodySize+1))
+		largest := post(handler, "def", strings.Repeat("a", maxIdempotentBodySize))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, tooLarge.Code)
+		assert.Equal(t, http.StatusCreated, largest.Code)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("It does not store server errors", func(t *testing.T) {
+		handler, calls := newHandler(http.StatusInternalServerError)
+
+		post(handler, "abc", `{"name":"sports"}`)
+		post(handler, "abc", `{"name":"sports"}`)
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("It ignores the requests without a key", func(t *testing.T) {
+		handler, calls := newHandler(http.StatusCreated)
+
+		post(handler, "", `{"name":"sports"}`)
+		post(handler, "", `{"name":"sports"}`)
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("It refuses the keys of the anonymous requests", func(t *testing.T) {
+		handler, calls := newHandler(http.StatusCreated)
+
+		r := httptest.NewRequest("POST", "/v1/category/", strings.NewReader(`{"name":"sports"}`))
+		r.Header.Set(IdempotencyKeyHeader, "abc")
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, 0, *calls)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-chi/render"
+	"github.com/gregbiv/news-api/pkg/api"
 	"github.com/gregbiv/news-api/pkg/assets/docs"
 	"github.com/gregbiv/news-api/pkg/context"
 	"github.com/xeipuuv/gojsonschema"
@@ -17,50 +19,24 @@ import (
 // JSONRequestSchema middleware that will validate the provided request based on a json schema
 func JSONRequestSchema(schema string) func(next http.Handler) http.Handler {
 	// Create the schema loader
-	schemaLoader := gojsonschema.NewBytesLoader(
-		docs.MustAsset(fmt.Sprintf("schema/request/%s", schema)),
-	)
+	schemaLoader := requestSchemaLoader(schema)
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Load the request body
 			requestBody, err := ioutil.ReadAll(r.Body)
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, map[string]string{
-					"code":    "InternalError",
-					"message": "Failed to read the request body",
-				})
-				return
-			}
-			requestLoader := gojsonschema.NewBytesLoader(requestBody)
-
-			// Validate the JSON schema
-			result, err := gojsonschema.Validate(schemaLoader, requestLoader)
-			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				render.JSON(w, r, map[string]string{
-					"code":    "InternalError",
-					"message": "Failed to validate against schema",
-				})
-
 				context.Logger(r.Context()).Error(err)
+				api.RenderErrResponse(w, r, api.ErrRender(
+					"InternalError",
+					"",
+					"Failed to read the request body",
+					http.StatusInternalServerError,
+				))
 				return
 			}
 
-			// Handle invalid requests in a nice and pretty way
-			if !result.Valid() {
-				details := make(map[string]string, len(result.Errors()))
-				for _, schemaErr := range result.Errors() {
-					details[schemaErr.Field()] = schemaErr.String()
-				}
-
-				w.WriteHeader(http.StatusBadRequest)
-				render.JSON(w, r, map[string]interface{}{
-					"code":    "ResponseError",
-					"message": fmt.Sprintf("Response schema validation failed for %s", schema),
-					"details": details,
-				})
+			if !validateRequest(w, r, schema, schemaLoader, requestBody) {
 				return
 			}
 
@@ -70,6 +46,172 @@ func JSONRequestSchema(schema string) func(next http.Handler) http.Handler {
 	}
 }
 
+// ValidateJSONSchema validates a document built by a handler (i.e. a patched resource)
+// against a request json schema. When the document is invalid the errors are rendered
+// the same way JSONRequestSchema does and false is returned.
+func ValidateJSONSchema(w http.ResponseWriter, r *http.Request, schema string, document []byte) bool {
+	return validateRequest(w, r, schema, requestSchemaLoader(schema), document)
+}
+
+// SchemaValidationErrors validates a document that is only part of a request (i.e. an operation of
+// a batch) against a request json schema and returns the validation errors, if any, instead of rendering them
+func SchemaValidationErrors(schema string, document []byte) ([]api.ValidationError, error) {
+	result, err := gojsonschema.Validate(requestSchemaLoader(schema), gojsonschema.NewBytesLoader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	return validationErrors(result.Errors()), nil
+}
+
+func requestSchemaLoader(schema string) gojsonschema.JSONLoader {
+	return gojsonschema.NewBytesLoader(
+		docs.MustAsset(fmt.Sprintf("schema/request/%s", schema)),
+	)
+}
+
+func validateRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	schema string,
+	schemaLoader gojsonschema.JSONLoader,
+	document []byte,
+) bool {
+	requestLoader := gojsonschema.NewBytesLoader(document)
+
+	// Validate the JSON schema
+	result, err := gojsonschema.Validate(schemaLoader, requestLoader)
+	if err != nil {
+		context.Logger(r.Context()).Error(err)
+		api.RenderErrResponse(w, r, api.ErrRender(
+			"InternalError",
+			"",
+			"Failed to validate against schema",
+			http.StatusInternalServerError,
+		))
+		return false
+	}
+
+	// Handle invalid requests in a nice and pretty way
+	if !result.Valid() {
+		api.RenderValidationErrors(w, r, validationErrors(result.Errors()))
+		return false
+	}
+
+	return true
+}
+
+// schemaKeywords maps the error types of gojsonschema to the JSON schema keywords that failed
+var schemaKeywords = map[string]string{
+	"required":                        "required",
+	"invalid_type":                    "type",
+	"number_any_of":                   "anyOf",
+	"number_one_of":                   "oneOf",
+	"number_all_of":                   "allOf",
+	"number_not":                      "not",
+	"missing_dependency":              "dependencies",
+	"enum":                            "enum",
+	"array_no_additional_items":       "additionalItems",
+	"array_min_items":                 "minItems",
+	"array_max_items":                 "maxItems",
+	"unique":                          "uniqueItems",
+	"array_min_properties":            "minProperties",
+	"array_max_properties":            "maxProperties",
+	"additional_property_not_allowed": "additionalProperties",
+	"invalid_property_pattern":        "patternProperties",
+	"string_gte":                      "minLength",
+	"string_lte":                      "maxLength",
+	"pattern":                         "pattern",
+	"format":                          "format",
+	"multiple_of":                     "multipleOf",
+	"number_gte":                      "minimum",
+	"number_gt":                       "exclusiveMinimum",
+	"number_lte":                      "maximum",
+	"number_lt":                       "exclusiveMaximum",
+}
+
+// keywordCodes maps the JSON schema keywords to the codes of the validation errors, the
+// keywords that are missing are reported with the generic Invalid code
+var keywordCodes = map[string]string{
+	"required":             "Required",
+	"type":                 "InvalidType",
+	"enum":                 "NotAllowed",
+	"minItems":             "TooFewItems",
+	"maxItems":             "TooManyItems",
+	"uniqueItems":          "DuplicateItems",
+	"additionalProperties": "UnknownField",
+	"minLength":            "TooShort",
+	"maxLength":            "TooLong",
+	"pattern":              "PatternMismatch",
+	"format":               "InvalidFormat",
+	"multipleOf":           "NotMultiple",
+	"minimum":              "TooSmall",
+	"exclusiveMinimum":     "TooSmall",
+	"maximum":              "TooLarge",
+	"exclusiveMaximum":     "TooLarge",
+}
+
+// constraintDetails are the details of the gojsonschema errors holding the value of the failing constraint
+var constraintDetails = []string{"min", "max", "pattern", "format", "allowed", "expected", "multiple", "dependency"}
+
+// validationErrors describes the errors of a schema validation for the clients
+func validationErrors(schemaErrs []gojsonschema.ResultError) []api.ValidationError {
+	validationErrs := make([]api.ValidationError, 0, len(schemaErrs))
+	for _, schemaErr := range schemaErrs {
+		keyword, ok := schemaKeywords[schemaErr.Type()]
+		if !ok {
+			keyword = schemaErr.Type()
+		}
+
+		code, ok := keywordCodes[keyword]
+		if !ok {
+			code = "Invalid"
+		}
+
+		validationErr := api.ValidationError{
+			Pointer: jsonPointer(schemaErr),
+			Keyword: keyword,
+			Code:    code,
+			Message: schemaErr.Description(),
+		}
+
+		for _, detail := range constraintDetails {
+			if constraint, ok := schemaErr.Details()[detail]; ok {
+				validationErr.Constraint = constraint
+				break
+			}
+		}
+
+		validationErrs = append(validationErrs, validationErr)
+	}
+
+	return validationErrs
+}
+
+// jsonPointer returns the RFC 6901 JSON Pointer of the value failing the validation. The errors about
+// a property of an object, such as a missing required one, point to the property rather than the object.
+func jsonPointer(schemaErr gojsonschema.ResultError) string {
+	// The path is joined with a delimiter that cannot be part of the keys of a JSON document
+	path := strings.Split(schemaErr.Context().String("\x00"), "\x00")[1:]
+
+	switch schemaErr.Type() {
+	case "required", "additional_property_not_allowed", "invalid_property_pattern":
+		if property, ok := schemaErr.Details()["property"].(string); ok {
+			path = append(path, property)
+		}
+	}
+
+	pointer := ""
+	for _, token := range path {
+		pointer += "/" + pointerEscaper.Replace(token)
+	}
+
+	return pointer
+}
+
+// pointerEscaper escapes the reference tokens of the JSON Pointers
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 // JSONDebugResponseSchema middleware that will validate the provided request based on a json schema
 func JSONDebugResponseSchema(schemas map[int]string) func(next http.Handler) http.Handler {
 	// Do not enable response validation when not in debug mode
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["name", "title"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "maxLength": 5},
+		"title": {"type": "string"},
+		"tags": {
+			"type": "array",
+			"items": {"type": "string", "pattern": "^[a-z]+$"}
+		},
+		"a/b": {"type": "integer", "minimum": 1}
+	}
+}`
+
+func TestValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	validate := func(document string) []api.ValidationError {
+		result, err := gojsonschema.Validate(
+			gojsonschema.NewStringLoader(testSchema),
+			gojsonschema.NewStringLoader(document),
+		)
+		require.NoError(t, err)
+
+		return validationErrors(result.Errors())
+	}
+
+	t.Run("It points to the missing required properties", func(t *testing.T) {
+		errs := validate(`{"name": "news"}`)
+
+		if assert.Len(t, errs, 1) {
+			assert.Equal(t, "/title", errs[0].Pointer)
+			assert.Equal(t, "required", errs[0].Keyword)
+			assert.Equal(t, "Required", errs[0].Code)
+			assert.Nil(t, errs[0].Constraint)
+			assert.NotEmpty(t, errs[0].Message)
+		}
+	})
+
+	t.Run("It reports the failing keyword along with its constraint", func(t *testing.T) {
+		errs := validate(`{"name": "business", "title": "Business"}`)
+
+		if assert.Len(t, errs, 1) {
+			assert.Equal(t, api.ValidationError{
+				Pointer:    "/name",
+				Keyword:    "maxLength",
+				Constraint: 5,
+				Code:       "TooLong",
+				Message:    errs[0].Message,
+			}, errs[0])
+		}
+	})
+
+	t.Run("It points to the items of arrays and escapes the keys", func(t *testing.T) {
+		errs := validate(`{"name": "news", "title": "News", "tags": ["ok", "Not OK"], "a/b": 0}`)
+
+		pointers := make(map[string]string, len(errs))
+		for _, err := range errs {
+			pointers[err.Pointer] = err.Code
+		}
+
+		assert.Equal(t, map[string]string{"/tags/1": "PatternMismatch", "/a~1b": "TooSmall"}, pointers)
+	})
+
+	t.Run("It points to the unknown properties", func(t *testing.T) {
+		errs := validate(`{"name": "news", "title": "News", "extra": true}`)
+
+		if assert.Len(t, errs, 1) {
+			assert.Equal(t, "/extra", errs[0].Pointer)
+			assert.Equal(t, "UnknownField", errs[0].Code)
+		}
+	})
+}
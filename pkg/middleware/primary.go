@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/context"
+)
+
+const (
+	// PrimaryCookie is the cookie pinning the reads of a browser to the primary database until the time it holds
+	PrimaryCookie = "news_api_primary_until"
+	// ReadAfterHeader is sent along with the PrimaryCookie for the clients that do not keep cookies. Sending its
+	// value back in the request header of the same name pins the reads to the primary database the same way.
+	ReadAfterHeader = "X-Read-After"
+)
+
+// PrimaryReads middleware makes the requests read from the primary database when reading from a
+// lagging replica would be wrong: requests that write, as they read what they are about to change,
+// and, for the given window after a successful write, every request of the same client so that it
+// sees its own changes. A zero window disables the latter.
+func PrimaryReads(window time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if isSafeMethod(r.Method) {
+				if pinnedToPrimary(r, time.Now(), window) {
+					r = r.WithContext(context.WithPrimary(r.Context()))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if window > 0 {
+				w = &primaryPinWriter{ResponseWriter: w, window: window}
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithPrimary(r.Context())))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// primaryPinWriter pins the client to the primary database when the response is successful,
+// as the writes that failed did not change anything the client has to read back
+type primaryPinWriter struct {
+	http.ResponseWriter
+	window      time.Duration
+	wroteHeader bool
+}
+
+func (w *primaryPinWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if statusCode >= 200 && statusCode < 300 {
+		until := time.Now().Add(w.window)
+		value := strconv.FormatInt(until.UnixNano()/int64(time.Millisecond), 10)
+
+		http.SetCookie(w.ResponseWriter, &http.Cookie{
+			Name:     PrimaryCookie,
+			Value:    value,
+			Path:     "/",
+			Expires:  until,
+			MaxAge:   int((w.window + time.Second - 1) / time.Second),
+			HttpOnly: true,
+		})
+		w.Header().Set(ReadAfterHeader, value)
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *primaryPinWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// pinnedToPrimary tells whether the request carries a PrimaryCookie or a ReadAfterHeader that did not expire yet.
+// Both are set by the client, so the times further away than the window are ignored rather than pinning it forever.
+func pinnedToPrimary(r *http.Request, now time.Time, window time.Duration) bool {
+	value := r.Header.Get(ReadAfterHeader)
+	if cookie, err := r.Cookie(PrimaryCookie); value == "" && err == nil {
+		value = cookie.Value
+	}
+
+	until, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	nowMillis := now.UnixNano() / int64(time.Millisecond)
+
+	return nowMillis < until && until <= nowMillis+int64(window/time.Millisecond)
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimaryReads(t *testing.T) {
+	t.Parallel()
+
+	var usePrimary bool
+	handler := PrimaryReads(5 * time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usePrimary = context.UsePrimary(r.Context())
+
+		if status := r.URL.Query().Get("status"); status != "" {
+			code, _ := strconv.Atoi(status)
+			w.WriteHeader(code)
+		}
+		w.Write([]byte("{}"))
+	}))
+
+	millis := func(t time.Time) string {
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	}
+
+	t.Run("It reads from the replicas by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/category/1", nil))
+
+		assert.False(t, usePrimary)
+		assert.Empty(t, w.Header().Get("Set-Cookie"))
+		assert.Empty(t, w.Header().Get(ReadAfterHeader))
+	})
+
+	t.Run("It reads from the primary when writing and pins the client to it", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/v1/category/1", nil))
+
+		assert.True(t, usePrimary)
+		assert.NotEmpty(t, w.Header().Get(ReadAfterHeader))
+
+		cookies := w.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, PrimaryCookie, cookies[0].Name)
+			assert.Equal(t, 5, cookies[0].MaxAge)
+			assert.Equal(t, w.Header().Get(ReadAfterHeader), cookies[0].Value)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/category/1", nil)
+		r.AddCookie(cookies[0])
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.True(t, usePrimary)
+	})
+
+	t.Run("It does not pin the client when the write failed", func(t *testing.T) {
+		for _, status := range []string{"400", "409", "500"} {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/v1/category/1?status="+status, nil))
+
+			assert.True(t, usePrimary)
+			assert.Empty(t, w.Result().Cookies(), status)
+			assert.Empty(t, w.Header().Get(ReadAfterHeader), status)
+		}
+	})
+
+	t.Run("It pins the clients sending back the read-after header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/category?status=201", nil))
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/category/1", nil)
+		r.Header.Set(ReadAfterHeader, w.Header().Get(ReadAfterHeader))
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.True(t, usePrimary)
+	})
+
+	t.Run("It reads from the replicas once the pin expired", func(t *testing.T) {
+		expired := millis(time.Now().Add(-time.Second))
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/category/1", nil)
+		r.AddCookie(&http.Cookie{Name: PrimaryCookie, Value: expired})
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.False(t, usePrimary)
+
+		r = httptest.NewRequest(http.MethodGet, "/v1/category/1", nil)
+		r.Header.Set(ReadAfterHeader, expired)
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.False(t, usePrimary)
+	})
+
+	t.Run("It ignores the pins further away than the window", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/category/1", nil)
+		r.Header.Set(ReadAfterHeader, millis(time.Now().Add(time.Hour)))
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.False(t, usePrimary)
+	})
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	chiMiddleware "github.com/go-chi/chi/middleware"
+	"github.com/gregbiv/news-api/pkg/api"
+)
+
+// Timeout middleware cancels the context of the requests that run longer than the given timeout,
+// which aborts their pending queries. The requests that did not answer by then are answered with
+// a Timeout error. A zero timeout disables it.
+func Timeout(timeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			r = r.WithContext(ctx)
+			ww := chiMiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			if ctx.Err() == context.DeadlineExceeded && ww.Status() == 0 {
+				api.RenderTimeout(w, r)
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry is the mapping to the audit_log database table.
+type AuditEntry struct {
+	ID           int64           `json:"id"`
+	Actor        string          `json:"actor"`
+	ClaimedActor string          `json:"claimed_actor"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	Diff         json.RawMessage `json:"diff"`
+	RequestID    string          `json:"request_id"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
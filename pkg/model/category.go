@@ -1,8 +1,12 @@
 package model
 
+import "time"
+
 // Category is the mapping to the category database table.
 type Category struct {
-	CategoryID string `json:"category_id"`
-	Name       string `json:"name"`
-	Title      string `json:"title"`
+	CategoryID string     `json:"category_id"`
+	Name       string     `json:"name"`
+	Title      string     `json:"title"`
+	Version    int64      `json:"version"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
 }
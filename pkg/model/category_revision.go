@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// CategoryRevision is the mapping to the category_revision database table.
+// Revision numbers match the version the category had once the revision was written.
+type CategoryRevision struct {
+	CategoryID string    `json:"category_id"`
+	Revision   int64     `json:"revision"`
+	Name       string    `json:"name"`
+	Title      string    `json:"title"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
+}
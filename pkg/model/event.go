@@ -0,0 +1,19 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is the mapping to the outbox_event database table.
+// Events describe committed changes of a resource, i.e. category.updated.
+// The sequence orders the events by commit, it is only known once they are committed.
+type Event struct {
+	ID           int64           `json:"id"`
+	Sequence     int64           `json:"-"`
+	Type         string          `json:"type"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	Payload      json.RawMessage `json:"data"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
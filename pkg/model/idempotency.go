@@ -0,0 +1,10 @@
+package model
+
+import "net/http"
+
+// IdempotentResponse is the response stored for an idempotency key, it is replayed to the retries of the request.
+type IdempotentResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
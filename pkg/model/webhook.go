@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	// DeliveryPending is the status of a delivery that is (re)scheduled
+	DeliveryPending = "pending"
+	// DeliveryDelivered is the status of a delivery the subscriber acknowledged
+	DeliveryDelivered = "delivered"
+	// DeliveryDead is the status of a delivery that ran out of attempts
+	DeliveryDead = "dead"
+)
+
+// Webhook is the mapping to the webhook database table.
+type Webhook struct {
+	WebhookID  string    `json:"webhook_id"`
+	TargetURL  string    `json:"target_url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"-"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is the mapping to the webhook_delivery database table.
+// Claimed deliveries carry the webhook and the event they deliver.
+type WebhookDelivery struct {
+	DeliveryID    int64     `json:"delivery_id"`
+	WebhookID     string    `json:"webhook_id"`
+	EventID       int64     `json:"event_id"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	Webhook *Webhook `json:"-"`
+	Event   *Event   `json:"-"`
+}
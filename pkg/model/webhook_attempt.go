@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// WebhookAttempt is the mapping to the webhook_attempt database table.
+// It records every request sent to a subscriber, including the test pings
+// which are not related to any delivery. The response excerpt is kept for troubleshooting
+// but never rendered, so that the API cannot be used to read the responses of arbitrary endpoints.
+type WebhookAttempt struct {
+	AttemptID       int64     `json:"attempt_id"`
+	WebhookID       string    `json:"webhook_id"`
+	DeliveryID      *int64    `json:"delivery_id"`
+	EventType       string    `json:"event_type"`
+	StatusCode      *int      `json:"status_code"`
+	Latency         int64     `json:"latency_ms"`
+	ResponseExcerpt string    `json:"-"`
+	Error           string    `json:"error"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/api/webhook"
+	"github.com/gregbiv/news-api/pkg/middleware"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+)
+
+// RouteAdmin registers the administrator routes
+func RouteAdmin(urlExtractor api.URLExtractor, db *sqlx.DB) func(r chi.Router) {
+	deliveryLister := storageWebhook.NewDeliveryLister(db)
+	requeuer := storageWebhook.NewRequeuer(db)
+
+	return func(r chi.Router) {
+		r.Use(middleware.RequireAdmin)
+
+		r.Route("/webhook-deliveries", func(r chi.Router) {
+			r.With(
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusOK:         "list_webhook_deliveries.json",
+					http.StatusBadRequest: "error.json",
+				}),
+			).Get("/", webhook.NewListDeliveriesHandler(deliveryLister).ServeHTTP)
+
+			r.With(
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusNotFound: "error.json",
+				}),
+			).Post("/{delivery_id}/retry", webhook.NewRetryDeliveryHandler(requeuer, urlExtractor).ServeHTTP)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/gregbiv/news-api/pkg/api/audit"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/middleware"
+	storageAudit "github.com/gregbiv/news-api/pkg/storage/audit"
+	"net/http"
+)
+
+// RouteAudit registers audit log routes
+func RouteAudit(reader database.Reader) func(r chi.Router) {
+	lister := storageAudit.NewLister(reader)
+
+	return func(r chi.Router) {
+		// The entries hold the full changes of the resources, including the discarded ones
+		r.Use(middleware.RequireAdmin)
+
+		r.With(
+			middleware.JSONDebugResponseSchema(map[int]string{
+				http.StatusOK:         "list_audit.json",
+				http.StatusBadRequest: "error.json",
+				http.StatusForbidden:  "error.json",
+			}),
+		).Get("/", audit.NewListAuditHandler(lister).ServeHTTP)
+	}
+}
@@ -4,33 +4,46 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/gregbiv/news-api/pkg/api"
 	"github.com/gregbiv/news-api/pkg/api/category"
+	"github.com/gregbiv/news-api/pkg/identifier"
 	"github.com/gregbiv/news-api/pkg/middleware"
 	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
-	"github.com/jmoiron/sqlx"
 	"net/http"
 )
 
 // RouteCategory registers category routes
-func RouteCategory(urlExtractor api.URLExtractor, db *sqlx.DB) func(r chi.Router) {
-	getter := storageCategory.NewGetter(db)
-	storer := storageCategory.NewStorer(db)
-	updater := storageCategory.NewUpdater(db)
-	discarder := storageCategory.NewDiscarder(db)
+func RouteCategory(urlExtractor api.URLExtractor, storages storageCategory.Storages, assigner *identifier.Assigner) func(r chi.Router) {
+	getter := storages.Getter
+	storer := storages.Storer
+	updater := storages.Updater
+	discarder := storages.Discarder
+	restorer := storages.Restorer
+	revisionGetter := storages.RevisionGetter
+	batcher := storages.Batcher
 
 	return func(r chi.Router) {
 		r.With(
 			middleware.JSONRequestSchema("create_category.json"),
 			middleware.JSONDebugResponseSchema(map[int]string{
-				http.StatusOK:         "create_category.json",
+				http.StatusCreated:    "get_category.json",
 				http.StatusBadRequest: "error.json",
+				http.StatusConflict:   "error.json",
 			}),
-		).Post("/", category.NewPostCategoryHandler(getter, storer).ServeHTTP)
+		).Post("/", category.NewPostCategoryHandler(storer, assigner).ServeHTTP)
+		r.With(
+			middleware.JSONDebugResponseSchema(map[int]string{
+				http.StatusOK:         "batch_category.json",
+				http.StatusBadRequest: "error.json",
+				http.StatusNotFound:   "error.json",
+				http.StatusConflict:   "error.json",
+			}),
+		).Post("/$batch", category.NewBatchCategoryHandler(storer, updater, discarder, batcher, assigner).ServeHTTP)
 		r.Route("/{category_id}", func(r chi.Router) {
 			r.With(
 				middleware.JSONDebugResponseSchema(map[int]string{
 					http.StatusOK:         "get_category.json",
 					http.StatusNotFound:   "error.json",
 					http.StatusBadRequest: "error.json",
+					http.StatusForbidden:  "error.json",
 				}),
 			).Get("/", category.NewGetCategoryHandler(getter, urlExtractor).ServeHTTP)
 			r.With(
@@ -38,14 +51,66 @@ func RouteCategory(urlExtractor api.URLExtractor, db *sqlx.DB) func(r chi.Router
 				middleware.JSONDebugResponseSchema(map[int]string{
 					http.StatusOK:         "update_category.json",
 					http.StatusBadRequest: "error.json",
+					http.StatusNotFound:   "error.json",
+					http.StatusConflict:   "error.json",
 				}),
 			).Put("/", category.NewCategoryUpdateHandler(getter, updater, urlExtractor).ServeHTTP)
+			r.With(
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusOK:                   "update_category.json",
+					http.StatusBadRequest:           "error.json",
+					http.StatusNotFound:             "error.json",
+					http.StatusConflict:             "error.json",
+					http.StatusUnsupportedMediaType: "error.json",
+					http.StatusUnprocessableEntity:  "error.json",
+				}),
+			).Patch("/", category.NewPatchCategoryHandler(getter, updater, urlExtractor).ServeHTTP)
 			r.With(
 				middleware.JSONDebugResponseSchema(map[int]string{
 					http.StatusNotFound:   "error.json",
 					http.StatusBadRequest: "error.json",
 				}),
 			).Delete("/", category.NewDiscardCategoryHandler(getter, discarder, urlExtractor).ServeHTTP)
+			// Restoring undoes a discard, so it is restricted to the administrators like purging is
+			r.With(
+				middleware.RequireAdmin,
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusOK:         "get_category.json",
+					http.StatusNotFound:   "error.json",
+					http.StatusBadRequest: "error.json",
+					http.StatusForbidden:  "error.json",
+				}),
+			).Post("/restore", category.NewRestoreCategoryHandler(getter, restorer, urlExtractor).ServeHTTP)
+			r.Route("/revisions", func(r chi.Router) {
+				r.With(
+					middleware.JSONDebugResponseSchema(map[int]string{
+						http.StatusOK:         "list_category_revisions.json",
+						http.StatusNotFound:   "error.json",
+						http.StatusBadRequest: "error.json",
+					}),
+				).Get("/", category.NewListRevisionsHandler(getter, revisionGetter, urlExtractor).ServeHTTP)
+				r.Route("/{revision}", func(r chi.Router) {
+					r.With(
+						middleware.JSONDebugResponseSchema(map[int]string{
+							http.StatusOK:       "get_category_revision.json",
+							http.StatusNotFound: "error.json",
+						}),
+					).Get("/", category.NewGetRevisionHandler(getter, revisionGetter, urlExtractor).ServeHTTP)
+					r.With(
+						middleware.JSONDebugResponseSchema(map[int]string{
+							http.StatusOK:       "diff_category_revisions.json",
+							http.StatusNotFound: "error.json",
+						}),
+					).Get("/diff/{other_revision}", category.NewDiffRevisionsHandler(getter, revisionGetter, urlExtractor).ServeHTTP)
+					r.With(
+						middleware.JSONDebugResponseSchema(map[int]string{
+							http.StatusOK:       "get_category.json",
+							http.StatusNotFound: "error.json",
+							http.StatusConflict: "error.json",
+						}),
+					).Post("/restore", category.NewRestoreRevisionHandler(getter, revisionGetter, updater, storages.Transactor, urlExtractor).ServeHTTP)
+				})
+			})
 		})
 	}
 }
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	apiEvent "github.com/gregbiv/news-api/pkg/api/event"
+	"github.com/gregbiv/news-api/pkg/event"
+	"github.com/gregbiv/news-api/pkg/middleware"
+	"github.com/gregbiv/news-api/pkg/storage/outbox"
+	"github.com/jmoiron/sqlx"
+)
+
+// RouteEvent registers the event stream routes. The stream carries the same events as the WebSocket
+// route and requires the same authentication. Browsers cannot set headers when opening an EventSource
+// either, so the route also authenticates the bearer tokens sent in the query string by the given middleware.
+func RouteEvent(db *sqlx.DB, broker *event.Broker, authenticateAccessToken func(next http.Handler) http.Handler) func(r chi.Router) {
+	reader := outbox.NewReader(db)
+
+	return func(r chi.Router) {
+		r.With(
+			authenticateAccessToken,
+			middleware.RequireActor,
+		).Get("/", apiEvent.NewStreamEventsHandler(broker, reader).ServeHTTP)
+	}
+}
+
+// RouteWebSocket registers the WebSocket push route. Browsers cannot set headers when opening a WebSocket,
+// so the route also authenticates the bearer tokens sent in the query string by the given middleware.
+func RouteWebSocket(broker *event.Broker, authenticateAccessToken func(next http.Handler) http.Handler) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.With(
+			authenticateAccessToken,
+			middleware.RequireActor,
+		).Get("/", apiEvent.NewWebSocketHandler(broker).ServeHTTP)
+	}
+}
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/gregbiv/news-api/pkg/api"
+	"github.com/gregbiv/news-api/pkg/api/webhook"
+	"github.com/gregbiv/news-api/pkg/middleware"
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	webhookDispatch "github.com/gregbiv/news-api/pkg/webhook"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+)
+
+// RouteWebhook registers webhook subscription routes
+func RouteWebhook(urlExtractor api.URLExtractor, db *sqlx.DB, sender webhookDispatch.Sender) func(r chi.Router) {
+	getter := storageWebhook.NewGetter(db)
+	lister := storageWebhook.NewLister(db)
+	storer := storageWebhook.NewStorer(db)
+	updater := storageWebhook.NewUpdater(db)
+	deleter := storageWebhook.NewDeleter(db)
+	attemptRecorder := storageWebhook.NewAttemptRecorder(db)
+	attemptLister := storageWebhook.NewAttemptLister(db)
+
+	return func(r chi.Router) {
+		// Subscribers receive every change and the test pings make the server call their target
+		r.Use(middleware.RequireAdmin)
+
+		r.With(
+			middleware.JSONDebugResponseSchema(map[int]string{
+				http.StatusOK:         "list_webhooks.json",
+				http.StatusBadRequest: "error.json",
+			}),
+		).Get("/", webhook.NewListWebhooksHandler(lister).ServeHTTP)
+		r.With(
+			middleware.JSONDebugResponseSchema(map[int]string{
+				http.StatusCreated:    "get_webhook.json",
+				http.StatusBadRequest: "error.json",
+			}),
+		).Post("/", webhook.NewPostWebhookHandler(storer).ServeHTTP)
+		r.Route("/{webhook_id}", func(r chi.Router) {
+			r.With(
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusOK:       "get_webhook.json",
+					http.StatusNotFound: "error.json",
+				}),
+			).Get("/", webhook.NewGetWebhookHandler(getter, urlExtractor).ServeHTTP)
+			r.With(
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusOK:         "get_webhook.json",
+					http.StatusBadRequest: "error.json",
+					http.StatusNotFound:   "error.json",
+				}),
+			).Put("/", webhook.NewPutWebhookHandler(getter, updater, urlExtractor).ServeHTTP)
+			r.With(
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusNotFound: "error.json",
+				}),
+			).Delete("/", webhook.NewDeleteWebhookHandler(deleter, urlExtractor).ServeHTTP)
+			r.With(
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusOK:       "get_webhook_attempt.json",
+					http.StatusNotFound: "error.json",
+				}),
+			).Post("/test", webhook.NewTestWebhookHandler(getter, attemptRecorder, sender, urlExtractor).ServeHTTP)
+			r.With(
+				middleware.JSONDebugResponseSchema(map[int]string{
+					http.StatusOK:         "list_webhook_attempts.json",
+					http.StatusBadRequest: "error.json",
+					http.StatusNotFound:   "error.json",
+				}),
+			).Get("/deliveries", webhook.NewListAttemptsHandler(getter, attemptLister, urlExtractor).ServeHTTP)
+		})
+	}
+}
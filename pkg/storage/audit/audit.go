@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	appContext "github.com/gregbiv/news-api/pkg/context"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// ActionCreate is the audited action of creating a resource
+	ActionCreate = "create"
+	// ActionUpdate is the audited action of updating a resource
+	ActionUpdate = "update"
+	// ActionDelete is the audited action of discarding a resource
+	ActionDelete = "delete"
+	// ActionRestore is the audited action of restoring a discarded resource
+	ActionRestore = "restore"
+	// ActionPurge is the audited action of permanently removing a resource
+	ActionPurge = "purge"
+)
+
+type (
+	// Recorder is the object responsible for recording audit entries.
+	// Entries are recorded within the transaction of the audited change.
+	Recorder interface {
+		Record(ctx context.Context, tx *sqlx.Tx, entry *model.AuditEntry) error
+	}
+
+	// Lister is the object responsible for listing the audit entries of a resource
+	Lister interface {
+		// ListByResource lists the audit entries of a resource, newest first
+		ListByResource(ctx context.Context, resourceType, resourceID string, skip, top *uint64) ([]*model.AuditEntry, error)
+	}
+
+	// auditEntry describes an audit_log db model
+	auditEntry struct {
+		ID           int64     `db:"audit_log_id"`
+		Actor        string    `db:"actor"`
+		ClaimedActor string    `db:"claimed_actor"`
+		Action       string    `db:"action"`
+		ResourceType string    `db:"resource_type"`
+		ResourceID   string    `db:"resource_id"`
+		Diff         []byte    `db:"diff"`
+		RequestID    string    `db:"request_id"`
+		CreatedAt    time.Time `db:"created_at"`
+	}
+)
+
+// NewEntry builds an audit entry of a change made by the actor of the given context. The actor the
+// client claims to act on behalf of is recorded separately, as it is not authenticated.
+// The before and after states are nil when the resource did not or does not exist anymore.
+func NewEntry(
+	ctx context.Context,
+	action string,
+	resourceType string,
+	resourceID string,
+	before interface{},
+	after interface{},
+) (*model.AuditEntry, error) {
+	changes, err := storage.Diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuditEntry{
+		Actor:        appContext.Actor(ctx),
+		ClaimedActor: appContext.ClaimedActor(ctx),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Diff:         diff,
+		RequestID:    appContext.RequestID(ctx),
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+func (e *auditEntry) toModel() *model.AuditEntry {
+	return &model.AuditEntry{
+		ID:           e.ID,
+		Actor:        e.Actor,
+		ClaimedActor: e.ClaimedActor,
+		Action:       e.Action,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		Diff:         json.RawMessage(e.Diff),
+		RequestID:    e.RequestID,
+		CreatedAt:    e.CreatedAt,
+	}
+}
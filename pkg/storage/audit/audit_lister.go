@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"context"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+type dbLister struct {
+	reader database.Reader
+}
+
+// NewLister inits and returns an instance of audit Lister reading from the given database
+func NewLister(reader database.Reader) Lister {
+	return &dbLister{reader: reader}
+}
+
+func (dl *dbLister) ListByResource(ctx context.Context, resourceType, resourceID string, skip, top *uint64) ([]*model.AuditEntry, error) {
+	query := `
+        SELECT
+            audit_log_id,
+            actor,
+            claimed_actor,
+            action,
+            resource_type,
+            resource_id,
+            diff,
+            request_id,
+            created_at
+        FROM
+            audit_log
+        WHERE
+            resource_type = ?
+            AND resource_id = ?
+        ORDER BY audit_log_id DESC
+        LIMIT ?
+        OFFSET ?
+    `
+
+	offset, limit := storage.OffsetLimit(skip, top)
+
+	db := database.Conn(ctx, dl.reader.Reader(ctx))
+
+	var dbEntries []auditEntry
+	if err := sqlx.SelectContext(ctx, db, &dbEntries, db.Rebind(query), resourceType, resourceID, limit, offset); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*model.AuditEntry, 0, len(dbEntries))
+	for i := range dbEntries {
+		entries = append(entries, dbEntries[i].toModel())
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/palantir/stacktrace"
+)
+
+type dbRecorder struct{}
+
+// NewRecorder inits and returns an instance of audit Recorder
+func NewRecorder() Recorder {
+	return &dbRecorder{}
+}
+
+func (dr *dbRecorder) Record(ctx context.Context, tx *sqlx.Tx, entry *model.AuditEntry) error {
+	query := `
+        INSERT INTO audit_log
+        (
+            actor,
+            claimed_actor,
+            action,
+            resource_type,
+            resource_id,
+            diff,
+            request_id,
+            created_at
+        )
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+    `
+
+	ID, err := database.InsertID(ctx,
+		tx,
+		query,
+		"audit_log_id",
+		entry.Actor,
+		entry.ClaimedActor,
+		entry.Action,
+		entry.ResourceType,
+		entry.ResourceID,
+		[]byte(entry.Diff),
+		entry.RequestID,
+		entry.CreatedAt,
+	)
+	entry.ID = ID
+
+	return stacktrace.Propagate(err, "failed to store data into audit_log table")
+}
@@ -1,71 +1,247 @@
 package category
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"github.com/gregbiv/news-api/pkg/database"
 	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/gregbiv/news-api/pkg/storage/audit"
+	"github.com/gregbiv/news-api/pkg/storage/outbox"
 	"github.com/jmoiron/sqlx"
 	"github.com/satori/go.uuid"
+	"time"
+)
+
+const (
+	// ResourceType identifies categories in the audit log and in events
+	ResourceType = "category"
+
+	// EventCreated is the type of the event published when a category is created
+	EventCreated = "category.created"
+	// EventUpdated is the type of the event published when a category is updated or restored
+	EventUpdated = "category.updated"
+	// EventDeleted is the type of the event published when a category is discarded
+	EventDeleted = "category.deleted"
+
+	// OperationCreate creates a category within a batch
+	OperationCreate = "create"
+	// OperationUpdate updates a category within a batch
+	OperationUpdate = "update"
+	// OperationDelete discards a category within a batch
+	OperationDelete = "delete"
+
+	// ColumnName is the category column holding the name
+	ColumnName = "name"
+	// ColumnTitle is the category column holding the title
+	ColumnTitle = "title"
 )
 
 var (
-	// ErrCategoryNotFound ...
-	ErrCategoryNotFound = errors.New("Unknown category")
+	// actionEvents maps the audited actions to the events they publish
+	actionEvents = map[string]string{
+		audit.ActionCreate:  EventCreated,
+		audit.ActionUpdate:  EventUpdated,
+		audit.ActionRestore: EventUpdated,
+		audit.ActionDelete:  EventDeleted,
+	}
+
+	// ErrCategoryNotFound is being returned when the requested category does not exist
+	ErrCategoryNotFound = storage.NewError(storage.NotFound, "category_id", "Unknown category")
+
+	// ErrVersionConflict is being returned when the category was modified since the expected version was read
+	ErrVersionConflict = storage.NewError(storage.Conflict, "version", "The category was modified by someone else")
+
+	// ErrCategoryExists is being returned when storing a category whose ID is already taken
+	ErrCategoryExists = storage.NewError(storage.Conflict, "category_id", "The category already exists")
 )
 
 type (
 	// Storer describes logic
 	// for persisting a category
 	Storer interface {
-		Store(category *model.Category) error
+		Store(ctx context.Context, category *model.Category) error
 	}
 
 	// Discarder describes logic
 	// for discarding a category
 	Discarder interface {
-		Discard(ID string) error
+		// Discard soft deletes a category, it can be restored until it is purged
+		Discard(ctx context.Context, ID string) error
+	}
+
+	// Restorer describes logic
+	// for restoring a discarded category
+	Restorer interface {
+		Restore(ctx context.Context, ID string) error
+	}
+
+	// Purger describes logic for permanently
+	// removing discarded categories
+	Purger interface {
+		// Purge removes the categories discarded before the given time and returns how many were removed
+		Purge(ctx context.Context, before time.Time) (int64, error)
 	}
 
 	// Getter is the object responsible for getting a category
 	Getter interface {
-		// GetCategoryBySubscriptionAndInterval gets a category model from the database given an subscriptionId and DeliveryIntervalId
-		GetCategoryByID(ID string) (*model.Category, error)
+		// GetCategoryByID gets a category model from the database, discarded categories are not found
+		GetCategoryByID(ctx context.Context, ID string) (*model.Category, error)
+		// GetCategoryByIDIncludingDeleted gets a category model from the database even if it was discarded
+		GetCategoryByIDIncludingDeleted(ctx context.Context, ID string) (*model.Category, error)
 	}
 
 	// Updater is the object responsible for updating a category
 	Updater interface {
-		//Update updates a category in the database given an updated category model
-		Update(category *model.Category) error
+		// Update updates a category in the database given an updated category model
+		// and the version the caller expects the stored category to be at
+		Update(ctx context.Context, category *model.Category, version int64) error
+		// UpdateColumns works like Update but only persists the given columns
+		UpdateColumns(ctx context.Context, category *model.Category, version int64, columns []string) error
+	}
+
+	// Batcher is the object responsible for applying several changes at once
+	Batcher interface {
+		// Batch applies all the operations in a single transaction or none of them,
+		// the failing operation is reported through a BatchError
+		Batch(ctx context.Context, operations []Operation) error
+	}
+
+	// Operation describes a single change of a batch, the version
+	// is the one an update expects the stored category to be at
+	Operation struct {
+		Op       string
+		Category *model.Category
+		Version  int64
+	}
+
+	// BatchError tells which operation made the batch fail
+	BatchError struct {
+		Index int
+		Err   error
 	}
 
 	// Asserter is the object responsible for asserting a category
 	Asserter interface {
-		AssertExists(ID string) (bool, error)
+		AssertExists(ctx context.Context, ID string) (bool, error)
+	}
+
+	// Storages bundles the category storages of a single backend
+	Storages struct {
+		Getter         Getter
+		Storer         Storer
+		Updater        Updater
+		Discarder      Discarder
+		Restorer       Restorer
+		RevisionGetter RevisionGetter
+		Batcher        Batcher
+		// Transactor runs the units of work joined by all the other storages
+		Transactor database.Transactor
 	}
 
 	// categoryManager handlers
 	// category Store, Publish and Discard
 	// operations
 	categoryManager struct {
-		db               *sqlx.DB
+		changeTracker
+		txManager        *database.TxManager
 		categoryAsserter Asserter
 	}
 
+	// changeTracker keeps track of the changes
+	// made to categories in the audit log and outbox
+	changeTracker struct {
+		recorder audit.Recorder
+		outbox   outbox.Writer
+	}
+
 	// Category describes a category db model
 	category struct {
-		CategoryID uuid.UUID `db:"category_id"`
-		Name       string    `db:"name"`
-		Title      string    `db:"title"`
+		CategoryID uuid.UUID  `db:"category_id"`
+		Name       string     `db:"name"`
+		Title      string     `db:"title"`
+		Version    int64      `db:"version"`
+		DeletedAt  *time.Time `db:"deleted_at"`
 	}
 )
 
+// NewStorages inits and returns the Postgres category storages, reads are made
+// through the given reader and changes within the transactions of the given manager
+func NewStorages(reader database.Reader, txManager *database.TxManager) Storages {
+	return Storages{
+		Getter:         NewGetter(reader),
+		Storer:         NewStorer(txManager),
+		Updater:        NewUpdater(txManager),
+		Discarder:      NewDiscarder(txManager),
+		Restorer:       NewRestorer(txManager),
+		RevisionGetter: NewRevisionGetter(reader),
+		Batcher:        NewBatcher(txManager),
+		Transactor:     txManager,
+	}
+}
+
 // newCategoryManager inits and returns
 // an instance of category manager
 func newCategoryManager(
-	db *sqlx.DB,
+	txManager *database.TxManager,
 	categoryAsserter Asserter,
 ) *categoryManager {
 	return &categoryManager{
-		db:               db,
+		changeTracker:    newChangeTracker(),
+		txManager:        txManager,
 		categoryAsserter: categoryAsserter,
 	}
 }
+
+func newChangeTracker() changeTracker {
+	return changeTracker{
+		recorder: audit.NewRecorder(),
+		outbox:   outbox.NewWriter(),
+	}
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d failed: %s", e.Index, e.Err)
+}
+
+func (c *category) toModel() *model.Category {
+	return &model.Category{
+		CategoryID: c.CategoryID.String(),
+		Name:       c.Name,
+		Title:      c.Title,
+		Version:    c.Version,
+		DeletedAt:  c.DeletedAt,
+	}
+}
+
+// track audits a change of a category and publishes the matching event,
+// both within the transaction making the change
+func (t *changeTracker) track(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	action string,
+	ID string,
+	before *model.Category,
+	after *model.Category,
+) error {
+	entry, err := audit.NewEntry(ctx, action, ResourceType, ID, before, after)
+	if err != nil {
+		return err
+	}
+
+	if err := t.recorder.Record(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	eventType, ok := actionEvents[action]
+	if !ok {
+		return nil
+	}
+
+	event, err := outbox.NewEvent(eventType, ResourceType, ID, after)
+	if err != nil {
+		return err
+	}
+
+	return t.outbox.Write(ctx, tx, event)
+}
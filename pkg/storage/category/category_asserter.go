@@ -1,31 +1,33 @@
 package category
 
 import (
+	"context"
 	"github.com/jmoiron/sqlx"
 )
 
 // dbCategoryAsserter implements Asserter interface
 type dbCategoryAsserter struct {
-	db sqlx.Queryer
+	db sqlx.ExtContext
 }
 
 // NewAsserter inits and returns an instance of category asserter
-func NewAsserter(db sqlx.Queryer) Asserter {
+func NewAsserter(db sqlx.ExtContext) Asserter {
 	return &dbCategoryAsserter{db}
 }
 
-func (s *dbCategoryAsserter) AssertExists(ID string) (bool, error) {
+func (s *dbCategoryAsserter) AssertExists(ctx context.Context, ID string) (bool, error) {
 	query := `
 		SELECT
 			count(category_id) as total
 		FROM
 			category
 		WHERE
-			category_id = $1`
+			category_id = ?
+			AND deleted_at IS NULL`
 
 	var total int
-	err := s.db.QueryRowx(
-		query,
+	err := s.db.QueryRowxContext(ctx,
+		s.db.Rebind(query),
 		ID,
 	).Scan(&total)
 
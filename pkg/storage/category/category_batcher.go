@@ -0,0 +1,87 @@
+package category
+
+import (
+	"context"
+	"fmt"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/jmoiron/sqlx"
+)
+
+type dbCategoryBatcher struct {
+	manager *categoryManager
+	updater *dbCategoryUpdater
+}
+
+// NewBatcher inits and returns
+// an instance of category Batcher
+func NewBatcher(txManager *database.TxManager) Batcher {
+	return &dbCategoryBatcher{
+		manager: newCategoryManager(txManager, nil),
+		updater: &dbCategoryUpdater{
+			changeTracker: newChangeTracker(),
+		},
+	}
+}
+
+func (b *dbCategoryBatcher) Batch(ctx context.Context, operations []Operation) error {
+	// The new versions are only handed out once they are committed
+	versions := make(map[*model.Category]int64, len(operations))
+
+	// The failing operation is only reported after the transaction, which is
+	// retried as a whole when the failure is caused by concurrent transactions
+	failed := -1
+	err := b.manager.txManager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		failed = -1
+		for i, operation := range operations {
+			if err := b.apply(ctx, tx, operation, versions); err != nil {
+				failed = i
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if failed >= 0 {
+			return &BatchError{Index: failed, Err: err}
+		}
+		return err
+	}
+
+	for category, version := range versions {
+		category.Version = version
+	}
+
+	return nil
+}
+
+func (b *dbCategoryBatcher) apply(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	operation Operation,
+	versions map[*model.Category]int64,
+) error {
+	switch operation.Op {
+	case OperationCreate:
+		return b.manager.storeCategory(ctx, tx, operation.Category)
+	case OperationUpdate:
+		after, err := b.updater.updateColumns(
+			ctx,
+			tx,
+			operation.Category,
+			operation.Version,
+			[]string{ColumnName, ColumnTitle},
+		)
+		if err != nil {
+			return err
+		}
+		versions[operation.Category] = after.Version
+		return nil
+	case OperationDelete:
+		return b.manager.discardCategory(ctx, tx, operation.Category.CategoryID)
+	}
+
+	return fmt.Errorf("Unknown operation %s", operation.Op)
+}
@@ -1,44 +1,157 @@
 package category
 
 import (
+	"context"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/storage/audit"
 	"github.com/jmoiron/sqlx"
 	"github.com/palantir/stacktrace"
-	log "github.com/sirupsen/logrus"
+	"time"
 )
 
 // NewDiscarder inits and returns
 // an instance of category Discarder
-func NewDiscarder(db *sqlx.DB) Discarder {
-	return newCategoryManager(db, nil)
+func NewDiscarder(txManager *database.TxManager) Discarder {
+	return newCategoryManager(txManager, nil)
 }
 
-func (m *categoryManager) Discard(ID string) error {
-	tx, err := m.db.Beginx()
+// NewRestorer inits and returns
+// an instance of category Restorer
+func NewRestorer(txManager *database.TxManager) Restorer {
+	return newCategoryManager(txManager, nil)
+}
+
+// NewPurger inits and returns
+// an instance of category Purger
+func NewPurger(txManager *database.TxManager) Purger {
+	return newCategoryManager(txManager, nil)
+}
+
+func (m *categoryManager) Discard(ctx context.Context, ID string) error {
+	return m.txManager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return m.discardCategory(ctx, tx, ID)
+	})
+}
+
+func (m *categoryManager) Restore(ctx context.Context, ID string) error {
+	return m.txManager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return m.restoreCategory(ctx, tx, ID)
+	})
+}
+
+func (m *categoryManager) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged []category
+	err := m.txManager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if purged, err = purgeCategories(ctx, tx, before); err != nil {
+			return stacktrace.Propagate(err, "Failed to purge categories discarded before %s", before)
+		}
+
+		for i := range purged {
+			ID := purged[i].CategoryID.String()
+			if err := m.track(ctx, tx, audit.ActionPurge, ID, purged[i].toModel(), nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	return int64(len(purged)), nil
+}
+
+// purgeCategories deletes the categories discarded before the given time and returns them.
+// The SQLite driver cannot return the deleted rows, so they are selected and deleted one by one.
+// SQLite compares the times as text, so they are all stored and compared in UTC.
+func purgeCategories(ctx context.Context, tx *sqlx.Tx, before time.Time) ([]category, error) {
+	before = before.UTC()
+
+	var purged []category
+	if tx.DriverName() != database.SQLite {
+		query := `
+			DELETE
+				FROM category
+				WHERE deleted_at < $1
+				RETURNING category_id, name, title, version, deleted_at`
+
+		err := tx.SelectContext(ctx, &purged, query, before)
+
+		return purged, err
+	}
+
+	query := `
+		SELECT category_id, name, title, version, deleted_at
+			FROM category
+			WHERE deleted_at < ?`
+
+	if err := tx.SelectContext(ctx, &purged, query, before); err != nil {
+		return nil, err
 	}
 
-	if err := discardCategory(tx, ID); err != nil {
-		if err == ErrCategoryNotFound {
-			return err
+	for i := range purged {
+		ID := purged[i].CategoryID.String()
+		if err := executeSingleRow(ctx, tx, `DELETE FROM category WHERE category_id = ?`, ID); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to purge category %s", ID)
 		}
-		log.Panic(stacktrace.Propagate(err, "Failed to discard category", ID))
 	}
 
-	if err := tx.Commit(); err != nil {
-		log.Panic(stacktrace.Propagate(err, "Failed to commit transaction to delete a category (Category ID: %s)", ID))
+	return purged, nil
+}
+
+func (m *categoryManager) discardCategory(ctx context.Context, tx *sqlx.Tx, ID string) error {
+	before, err := lockCategory(ctx, tx, ID, false)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	after := *before
+	deletedAt := time.Now().UTC()
+	after.DeletedAt = &deletedAt
+
+	query := `
+		UPDATE category
+			SET deleted_at = ?
+			WHERE category_id = ?
+				AND deleted_at IS NULL`
+
+	if err := executeSingleRow(ctx, tx, query, deletedAt, ID); err != nil {
+		return err
+	}
+
+	return m.track(ctx, tx, audit.ActionDelete, ID, before, &after)
 }
 
-func discardCategory(tx *sqlx.Tx, ID string) error {
+func (m *categoryManager) restoreCategory(ctx context.Context, tx *sqlx.Tx, ID string) error {
+	before, err := lockCategory(ctx, tx, ID, true)
+	if err != nil {
+		return err
+	}
+
+	// Restoring a category that was not discarded is a no-op
+	if before.DeletedAt == nil {
+		return nil
+	}
+
+	after := *before
+	after.DeletedAt = nil
+
 	query := `
-		DELETE
-			FROM category
-			WHERE category_id = $1`
+		UPDATE category
+			SET deleted_at = NULL
+			WHERE category_id = ?`
+
+	if err := executeSingleRow(ctx, tx, query, ID); err != nil {
+		return err
+	}
+
+	return m.track(ctx, tx, audit.ActionRestore, ID, before, &after)
+}
 
-	result, err := tx.Exec(query, ID)
+func executeSingleRow(ctx context.Context, tx *sqlx.Tx, query string, args ...interface{}) error {
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
 	if err != nil {
 		return err
 	}
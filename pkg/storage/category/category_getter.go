@@ -1,38 +1,51 @@
 package category
 
 import (
+	"context"
 	"database/sql"
+	"github.com/gregbiv/news-api/pkg/database"
 	"github.com/gregbiv/news-api/pkg/model"
 	"github.com/jmoiron/sqlx"
 )
 
 type (
 	dbGetter struct {
-		db *sqlx.DB
+		reader database.Reader
 	}
 )
 
-// NewGetter inits and returns a Getter instance
-func NewGetter(db *sqlx.DB) Getter {
-	return &dbGetter{db: db}
+// NewGetter inits and returns a Getter instance reading from the given database
+func NewGetter(reader database.Reader) Getter {
+	return &dbGetter{reader: reader}
 }
 
-func (dg *dbGetter) GetCategoryByID(ID string) (*model.Category, error) {
+func (dg *dbGetter) GetCategoryByID(ctx context.Context, ID string) (*model.Category, error) {
+	return dg.getCategory(ctx, ID, false)
+}
+
+func (dg *dbGetter) GetCategoryByIDIncludingDeleted(ctx context.Context, ID string) (*model.Category, error) {
+	return dg.getCategory(ctx, ID, true)
+}
+
+func (dg *dbGetter) getCategory(ctx context.Context, ID string, includeDeleted bool) (*model.Category, error) {
 	dbCategory := category{}
 
 	query := `
         SELECT
             category_id,
 			name,
-			title
+			title,
+			version,
+			deleted_at
         FROM
             category
 		WHERE
-			category_id = $1
-        ORDER BY order DESC
+			category_id = ?
+			AND (CAST(? AS BOOLEAN) OR deleted_at IS NULL)
 	`
 
-	err := dg.db.Get(&dbCategory, query, ID)
+	db := database.Conn(ctx, dg.reader.Reader(ctx))
+	err := sqlx.GetContext(ctx, db, &dbCategory, db.Rebind(query), ID, includeDeleted)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -42,10 +55,37 @@ func (dg *dbGetter) GetCategoryByID(ID string) (*model.Category, error) {
 		return nil, err
 	}
 
-	modelCategory := &model.Category{}
-	modelCategory.CategoryID = dbCategory.CategoryID.String()
-	modelCategory.Name = dbCategory.Name
-	modelCategory.Title = dbCategory.Title
+	return dbCategory.toModel(), nil
+}
+
+// lockCategory gets a category within a transaction and locks
+// its row until the transaction is committed or rolled back
+func lockCategory(ctx context.Context, tx *sqlx.Tx, ID string, includeDeleted bool) (*model.Category, error) {
+	dbCategory := category{}
+
+	query := `
+        SELECT
+            category_id,
+			name,
+			title,
+			version,
+			deleted_at
+        FROM
+            category
+		WHERE
+			category_id = ?
+			AND (CAST(? AS BOOLEAN) OR deleted_at IS NULL)
+		` + database.ForUpdate(tx)
+
+	err := tx.GetContext(ctx, &dbCategory, tx.Rebind(query), ID, includeDeleted)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCategoryNotFound
+		}
+
+		return nil, err
+	}
 
-	return modelCategory, nil
+	return dbCategory.toModel(), nil
 }
@@ -0,0 +1,150 @@
+package category
+
+import (
+	"context"
+	"database/sql"
+	appContext "github.com/gregbiv/news-api/pkg/context"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/palantir/stacktrace"
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// ErrRevisionNotFound is being returned when the requested category revision does not exist
+var ErrRevisionNotFound = storage.NewError(storage.NotFound, "revision", "Unknown category revision")
+
+type (
+	// RevisionGetter is the object responsible for getting the revisions of a category
+	RevisionGetter interface {
+		// ListRevisions lists the revisions of a category, newest first
+		ListRevisions(ctx context.Context, ID string, skip, top *uint64) ([]*model.CategoryRevision, error)
+		// GetRevision gets a single revision of a category
+		GetRevision(ctx context.Context, ID string, revision int64) (*model.CategoryRevision, error)
+	}
+
+	dbRevisionGetter struct {
+		reader database.Reader
+	}
+
+	// categoryRevision describes a category revision db model
+	categoryRevision struct {
+		CategoryID uuid.UUID `db:"category_id"`
+		Revision   int64     `db:"revision"`
+		Name       string    `db:"name"`
+		Title      string    `db:"title"`
+		Actor      string    `db:"actor"`
+		CreatedAt  time.Time `db:"created_at"`
+	}
+)
+
+// NewRevisionGetter inits and returns a RevisionGetter instance
+func NewRevisionGetter(reader database.Reader) RevisionGetter {
+	return &dbRevisionGetter{reader: reader}
+}
+
+func (dg *dbRevisionGetter) ListRevisions(ctx context.Context, ID string, skip, top *uint64) ([]*model.CategoryRevision, error) {
+	query := `
+        SELECT
+            category_id,
+			revision,
+			name,
+			title,
+			actor,
+			created_at
+        FROM
+            category_revision
+		WHERE
+			category_id = ?
+		ORDER BY revision DESC
+		LIMIT ?
+		OFFSET ?
+	`
+
+	offset, limit := storage.OffsetLimit(skip, top)
+
+	db := database.Conn(ctx, dg.reader.Reader(ctx))
+
+	var dbRevisions []categoryRevision
+	if err := sqlx.SelectContext(ctx, db, &dbRevisions, db.Rebind(query), ID, limit, offset); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*model.CategoryRevision, 0, len(dbRevisions))
+	for i := range dbRevisions {
+		revisions = append(revisions, dbRevisions[i].toModel())
+	}
+
+	return revisions, nil
+}
+
+func (dg *dbRevisionGetter) GetRevision(ctx context.Context, ID string, revision int64) (*model.CategoryRevision, error) {
+	dbRevision := categoryRevision{}
+
+	query := `
+        SELECT
+            category_id,
+			revision,
+			name,
+			title,
+			actor,
+			created_at
+        FROM
+            category_revision
+		WHERE
+			category_id = ?
+			AND revision = ?
+	`
+
+	db := database.Conn(ctx, dg.reader.Reader(ctx))
+	err := sqlx.GetContext(ctx, db, &dbRevision, db.Rebind(query), ID, revision)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRevisionNotFound
+		}
+
+		return nil, err
+	}
+
+	return dbRevision.toModel(), nil
+}
+
+func (r *categoryRevision) toModel() *model.CategoryRevision {
+	return &model.CategoryRevision{
+		CategoryID: r.CategoryID.String(),
+		Revision:   r.Revision,
+		Name:       r.Name,
+		Title:      r.Title,
+		Actor:      r.Actor,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// insertRevision writes the immutable revision of the
+// category as it is stored at its current version
+func insertRevision(ctx context.Context, tx *sqlx.Tx, category *model.Category) error {
+	query := `
+        INSERT INTO category_revision
+        (
+            category_id,
+            revision,
+            name,
+            title,
+            actor
+        )
+        VALUES (?, ?, ?, ?, ?)
+    `
+
+	_, err := tx.ExecContext(ctx,
+		tx.Rebind(query),
+		category.CategoryID,
+		category.Version,
+		category.Name,
+		category.Title,
+		appContext.Actor(ctx),
+	)
+
+	return stacktrace.Propagate(err, "failed to store data into category_revision table")
+}
@@ -0,0 +1,125 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/config"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStorages(t *testing.T) {
+	conf := config.Database{}
+	conf.PostgresDB.DSN = "sqlite3://:memory:"
+
+	cluster, err := database.OpenCluster(conf)
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	migrations, err := filepath.Glob("../../../resources/migrations/sqlite/*.up.sql")
+	require.NoError(t, err)
+	for _, migration := range migrations {
+		schema, err := ioutil.ReadFile(migration)
+		require.NoError(t, err)
+		_, err = cluster.Primary().Exec(string(schema))
+		require.NoError(t, err)
+	}
+
+	ctx := context.Background()
+	storages := NewStorages(cluster, database.NewTxManager(cluster.Primary()))
+	ID := "0b6e2d6e-7f9c-4c55-9f0e-2e51d8b0c7a1"
+
+	t.Run("It stores a category once", func(t *testing.T) {
+		category := &model.Category{CategoryID: ID, Name: "sport", Title: "Sport"}
+
+		assert.NoError(t, storages.Storer.Store(ctx, category))
+		assert.Equal(t, int64(1), category.Version)
+
+		assert.Equal(t, ErrCategoryExists, storages.Storer.Store(ctx, &model.Category{CategoryID: ID, Name: "sport", Title: "Sport"}))
+	})
+
+	t.Run("It updates a category at the expected version only", func(t *testing.T) {
+		category := &model.Category{CategoryID: ID, Name: "sports", Title: "Sports"}
+
+		assert.NoError(t, storages.Updater.Update(ctx, category, 1))
+		assert.Equal(t, int64(2), category.Version)
+		assert.Equal(t, ErrVersionConflict, storages.Updater.Update(ctx, category, 1))
+
+		stored, err := storages.Getter.GetCategoryByID(ctx, ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Sports", stored.Title)
+		assert.Equal(t, int64(2), stored.Version)
+
+		revisions, err := storages.RevisionGetter.ListRevisions(ctx, ID, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 2)
+	})
+
+	t.Run("It reads and writes within a unit of work spanning the storages", func(t *testing.T) {
+		// SQLite has a single connection, held by the transaction until it ends
+		timeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		err := storages.Transactor.InTx(timeout, func(ctx context.Context, tx *sqlx.Tx) error {
+			revision, err := storages.RevisionGetter.GetRevision(ctx, ID, 1)
+			require.NoError(t, err)
+
+			category, err := storages.Getter.GetCategoryByID(ctx, ID)
+			require.NoError(t, err)
+
+			category.Title = revision.Title
+			require.NoError(t, storages.Updater.Update(ctx, category, category.Version))
+
+			stored, err := storages.Getter.GetCategoryByID(ctx, ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Sport", stored.Title)
+
+			return errors.New("rolled back")
+		})
+		assert.EqualError(t, err, "rolled back")
+
+		stored, err := storages.Getter.GetCategoryByID(ctx, ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Sports", stored.Title)
+	})
+
+	t.Run("It discards, restores and purges a category", func(t *testing.T) {
+		assert.NoError(t, storages.Discarder.Discard(ctx, ID))
+		assert.Equal(t, ErrCategoryNotFound, storages.Discarder.Discard(ctx, ID))
+
+		_, err := storages.Getter.GetCategoryByID(ctx, ID)
+		assert.Equal(t, ErrCategoryNotFound, err)
+
+		discarded, err := storages.Getter.GetCategoryByIDIncludingDeleted(ctx, ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, discarded.DeletedAt)
+
+		assert.NoError(t, storages.Restorer.Restore(ctx, ID))
+		_, err = storages.Getter.GetCategoryByID(ctx, ID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, storages.Discarder.Discard(ctx, ID))
+		// The times are compared whatever their offset
+		before := time.Now().Add(time.Minute).In(time.FixedZone("HST", -10*60*60))
+		purged, err := NewPurger(database.NewTxManager(cluster.Primary())).Purge(ctx, before)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = storages.Getter.GetCategoryByIDIncludingDeleted(ctx, ID)
+		assert.Equal(t, ErrCategoryNotFound, err)
+	})
+
+	t.Run("It does not store the events of the changes", func(t *testing.T) {
+		var stored int
+		assert.NoError(t, cluster.Primary().Get(&stored, `SELECT COUNT(*) FROM outbox_event`))
+		assert.Equal(t, 0, stored)
+	})
+}
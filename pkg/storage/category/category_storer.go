@@ -1,54 +1,68 @@
 package category
 
 import (
+	"context"
+	"github.com/gregbiv/news-api/pkg/database"
 	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage/audit"
 	"github.com/jmoiron/sqlx"
 	"github.com/palantir/stacktrace"
 )
 
 // NewStorer inits and returns an instance
 // of category Storer
-func NewStorer(db *sqlx.DB) Storer {
-	return newCategoryManager(db, nil)
+func NewStorer(txManager *database.TxManager) Storer {
+	return newCategoryManager(txManager, nil)
 }
 
-func (m *categoryManager) Store(d *model.Category) error {
-	tx, err := m.db.Beginx()
-	if err != nil {
+func (m *categoryManager) Store(ctx context.Context, d *model.Category) error {
+	return m.txManager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return m.storeCategory(ctx, tx, d)
+	})
+}
+
+// storeCategory inserts the category along with its first revision within the given transaction
+func (m *categoryManager) storeCategory(ctx context.Context, tx *sqlx.Tx, d *model.Category) error {
+	if err := m.insertCategory(ctx, tx, d); err != nil {
 		return err
 	}
 
-	if err := m.insertCategory(tx, d); err != nil {
-		tx.Rollback()
+	if err := insertRevision(ctx, tx, d); err != nil {
 		return err
 	}
 
-	tx.Commit()
-	return nil
+	return m.track(ctx, tx, audit.ActionCreate, d.CategoryID, nil, d)
 }
 
-func (m *categoryManager) insertCategory(tx *sqlx.Tx, category *model.Category) error {
+func (m *categoryManager) insertCategory(ctx context.Context, tx *sqlx.Tx, category *model.Category) error {
 	query := `
         INSERT INTO category
         (
             category_id,
             name,
-            title
+            title,
+            version
         )
-        VALUES ($1, $2, $3)
+        VALUES (?, ?, ?, ?)
     `
 
-	stmt, err := tx.Prepare(query)
+	stmt, err := tx.PrepareContext(ctx, tx.Rebind(query))
 	if err != nil {
 		return stacktrace.Propagate(err, "failed to creates a prepared statement to store data in category table")
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(
+	category.Version = 1
+
+	_, err = stmt.ExecContext(ctx,
 		category.CategoryID,
 		category.Name,
 		category.Title,
+		category.Version,
 	)
+	if database.IsUniqueViolation(err) {
+		return ErrCategoryExists
+	}
 
 	return stacktrace.Propagate(err, "failed to store data into category table")
 }
@@ -1,79 +1,159 @@
 package category
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/gregbiv/news-api/pkg/database"
 	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage/audit"
 	"github.com/jmoiron/sqlx"
 	"github.com/palantir/stacktrace"
+	"strings"
 )
 
-//ErrUpdatingCategory is being thrown when something goes wrong while updating the category
+// ErrUpdatingCategory is being thrown when something goes wrong while updating the category
 var ErrUpdatingCategory = errors.New("Something went wrong when trying to update the category")
 
+// updatableColumns maps every column a category update
+// may touch to the model value that is persisted into it
+var updatableColumns = map[string]updatableColumn{
+	ColumnName: {
+		value: func(c *model.Category) interface{} { return c.Name },
+		copy:  func(source, target *model.Category) { target.Name = source.Name },
+	},
+	ColumnTitle: {
+		value: func(c *model.Category) interface{} { return c.Title },
+		copy:  func(source, target *model.Category) { target.Title = source.Title },
+	},
+}
+
 type (
 	dbCategoryUpdater struct {
-		db *sqlx.DB
+		changeTracker
+		txManager *database.TxManager
+	}
+
+	updatableColumn struct {
+		value func(c *model.Category) interface{}
+		copy  func(source, target *model.Category)
 	}
 )
 
 // NewUpdater inits and returns a CategoryUpdater instance
-func NewUpdater(db *sqlx.DB) Updater {
-	return &dbCategoryUpdater{db: db}
+func NewUpdater(txManager *database.TxManager) Updater {
+	return &dbCategoryUpdater{
+		changeTracker: newChangeTracker(),
+		txManager:     txManager,
+	}
+}
+
+func (du *dbCategoryUpdater) Update(ctx context.Context, category *model.Category, version int64) error {
+	return du.UpdateColumns(ctx, category, version, []string{ColumnName, ColumnTitle})
 }
 
-func (du *dbCategoryUpdater) Update(category *model.Category) error {
-	tx, err := du.db.Beginx()
+func (du *dbCategoryUpdater) UpdateColumns(
+	ctx context.Context,
+	category *model.Category,
+	version int64,
+	columns []string,
+) error {
+	var after *model.Category
+	err := du.txManager.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		after, err = du.updateColumns(ctx, tx, category, version, columns)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	steps := []func(*sqlx.Tx, *model.Category) (bool, error){
-		du.updateCategory,
+	category.Version = after.Version
+
+	return nil
+}
+
+// updateColumns updates the category within the given transaction and returns its new state
+func (du *dbCategoryUpdater) updateColumns(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	category *model.Category,
+	version int64,
+	columns []string,
+) (*model.Category, error) {
+	before, err := lockCategory(ctx, tx, category.CategoryID, false)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, step := range steps {
-		ok, err := step(tx, category)
-		if err != nil {
-			tx.Rollback()
-			stacktrace.Propagate(err, ErrUpdatingCategory.Error())
-			return err
-		}
+	if before.Version != version {
+		return nil, ErrVersionConflict
+	}
 
+	ok, err := du.updateCategory(ctx, tx, category, version, columns)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to update data in category table")
+	}
+
+	if !ok {
+		return nil, ErrUpdatingCategory
+	}
+
+	after := *before
+	after.Version = version + 1
+	for _, column := range columns {
+		updatableColumns[column].copy(category, &after)
+	}
+
+	if err := insertRevision(ctx, tx, &after); err != nil {
+		return nil, err
+	}
+
+	if err := du.track(ctx, tx, audit.ActionUpdate, category.CategoryID, before, &after); err != nil {
+		return nil, err
+	}
+
+	return &after, nil
+}
+
+func (du *dbCategoryUpdater) updateCategory(ctx context.Context,
+	tx *sqlx.Tx,
+	category *model.Category,
+	version int64,
+	columns []string,
+) (bool, error) {
+	assignments := make([]string, 0, len(columns)+1)
+	args := make([]interface{}, 0, len(columns)+2)
+
+	for _, column := range columns {
+		updatable, ok := updatableColumns[column]
 		if !ok {
-			tx.Rollback()
-			return ErrUpdatingCategory
+			return false, fmt.Errorf("Unknown category column %s", column)
 		}
+
+		args = append(args, updatable.value(category))
+		assignments = append(assignments, column+" = ?")
 	}
 
-	return tx.Commit()
-}
+	assignments = append(assignments, "version = version + 1")
+	args = append(args, category.CategoryID, version)
 
-func (du *dbCategoryUpdater) updateCategory(tx *sqlx.Tx, category *model.Category) (bool, error) {
-	query := `
+	query := fmt.Sprintf(`
         UPDATE
 			category
 		SET
-			(
-				name,
-				title
-			)
-        =
-			($1, $2)
+			%s
 		WHERE
-			category_id = $3
-    `
-
-	return du.executeQuery(
-		tx,
-		query,
-		category.Name,
-		category.Title,
-		category.CategoryID,
-	)
+			category_id = ?
+			AND version = ?
+			AND deleted_at IS NULL
+    `, strings.Join(assignments, ", "))
+
+	return du.executeQuery(ctx, tx, query, args...)
 }
 
-func (du *dbCategoryUpdater) executeQuery(tx *sqlx.Tx, query string, args ...interface{}) (bool, error) {
-	result, err := tx.Exec(query, args...)
+func (du *dbCategoryUpdater) executeQuery(ctx context.Context, tx *sqlx.Tx, query string, args ...interface{}) (bool, error) {
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
 	if err != nil {
 		return false, err
 	}
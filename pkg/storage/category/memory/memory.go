@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	appContext "github.com/gregbiv/news-api/pkg/context"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	_ storageCategory.Storer         = (*Storage)(nil)
+	_ storageCategory.Getter         = (*Storage)(nil)
+	_ storageCategory.Updater        = (*Storage)(nil)
+	_ storageCategory.Discarder      = (*Storage)(nil)
+	_ storageCategory.Restorer       = (*Storage)(nil)
+	_ storageCategory.RevisionGetter = (*Storage)(nil)
+	_ storageCategory.Batcher        = (*Storage)(nil)
+	_ storageCategory.Asserter       = (*Storage)(nil)
+	_ database.Transactor            = (*Storage)(nil)
+)
+
+// Storage keeps categories and their revisions in memory. It is safe for concurrent
+// use and fails the same way as the Postgres storage, but neither audits the
+// changes nor publishes events. Meant for tests and local demos.
+type Storage struct {
+	mu         sync.RWMutex
+	txMu       sync.Mutex
+	categories map[string]model.Category
+	revisions  map[string][]model.CategoryRevision
+}
+
+// New inits and returns an empty in-memory category storage
+func New() *Storage {
+	return &Storage{
+		categories: make(map[string]model.Category),
+		revisions:  make(map[string][]model.CategoryRevision),
+	}
+}
+
+// NewStorages inits and returns the category storages backed by a single in-memory storage
+func NewStorages() storageCategory.Storages {
+	s := New()
+
+	return storageCategory.Storages{
+		Getter:         s,
+		Storer:         s,
+		Updater:        s,
+		Discarder:      s,
+		Restorer:       s,
+		RevisionGetter: s,
+		Batcher:        s,
+		Transactor:     s,
+	}
+}
+
+// InTx runs the units of work one at a time and undoes the changes
+// of a unit of work that fails, the way Batch does
+func (s *Storage) InTx(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.RLock()
+	snapshot := s.snapshot()
+	s.mu.RUnlock()
+
+	if err := fn(ctx, nil); err != nil {
+		s.mu.Lock()
+		s.categories, s.revisions = snapshot.categories, snapshot.revisions
+		s.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Store stores a new category at its first version
+func (s *Storage) Store(ctx context.Context, category *model.Category) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store(ctx, category)
+}
+
+// GetCategoryByID gets a category, discarded categories are not found
+func (s *Storage) GetCategoryByID(ctx context.Context, ID string) (*model.Category, error) {
+	return s.get(ID, false)
+}
+
+// GetCategoryByIDIncludingDeleted gets a category even if it was discarded
+func (s *Storage) GetCategoryByIDIncludingDeleted(ctx context.Context, ID string) (*model.Category, error) {
+	return s.get(ID, true)
+}
+
+// Update updates the name and title of a category at the given version
+func (s *Storage) Update(ctx context.Context, category *model.Category, version int64) error {
+	return s.UpdateColumns(ctx, category, version, []string{storageCategory.ColumnName, storageCategory.ColumnTitle})
+}
+
+// UpdateColumns updates the given columns of a category at the given version
+func (s *Storage) UpdateColumns(ctx context.Context, category *model.Category, version int64, columns []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.update(ctx, category, version, columns)
+}
+
+// Discard soft deletes a category
+func (s *Storage) Discard(ctx context.Context, ID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.discard(ID)
+}
+
+// Restore restores a discarded category, restoring a category that was not discarded is a no-op
+func (s *Storage) Restore(ctx context.Context, ID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.categories[ID]
+	if !ok {
+		return storageCategory.ErrCategoryNotFound
+	}
+
+	stored.DeletedAt = nil
+	s.categories[ID] = stored
+
+	return nil
+}
+
+// AssertExists tells whether a category exists and was not discarded
+func (s *Storage) AssertExists(ctx context.Context, ID string) (bool, error) {
+	_, err := s.get(ID, false)
+	if err == storageCategory.ErrCategoryNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// ListRevisions lists the revisions of a category, newest first
+func (s *Storage) ListRevisions(ctx context.Context, ID string, skip, top *uint64) ([]*model.CategoryRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored := s.revisions[ID]
+	offset, limit := storage.OffsetLimit(skip, top)
+
+	revisions := make([]*model.CategoryRevision, 0)
+	for i := len(stored) - 1 - int(offset); i >= 0 && len(revisions) < int(limit); i-- {
+		revision := stored[i]
+		revisions = append(revisions, &revision)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision gets a single revision of a category
+func (s *Storage) GetRevision(ctx context.Context, ID string, revision int64) (*model.CategoryRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, stored := range s.revisions[ID] {
+		if stored.Revision == revision {
+			return &stored, nil
+		}
+	}
+
+	return nil, storageCategory.ErrRevisionNotFound
+}
+
+// Batch applies all the operations or none of them
+func (s *Storage) Batch(ctx context.Context, operations []storageCategory.Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.snapshot()
+
+	// The new versions are only handed out once every operation succeeded
+	versions := make(map[*model.Category]int64, len(operations))
+
+	for i, operation := range operations {
+		var err error
+		switch operation.Op {
+		case storageCategory.OperationCreate:
+			err = s.store(ctx, operation.Category)
+		case storageCategory.OperationUpdate:
+			updated := *operation.Category
+			err = s.update(ctx, &updated, operation.Version, []string{storageCategory.ColumnName, storageCategory.ColumnTitle})
+			versions[operation.Category] = updated.Version
+		case storageCategory.OperationDelete:
+			err = s.discard(operation.Category.CategoryID)
+		default:
+			err = fmt.Errorf("Unknown operation %s", operation.Op)
+		}
+
+		if err != nil {
+			s.categories, s.revisions = snapshot.categories, snapshot.revisions
+			return &storageCategory.BatchError{Index: i, Err: err}
+		}
+	}
+
+	for category, version := range versions {
+		category.Version = version
+	}
+
+	return nil
+}
+
+func (s *Storage) get(ID string, includeDeleted bool) (*model.Category, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, ok := s.categories[ID]
+	if !ok || (stored.DeletedAt != nil && !includeDeleted) {
+		return nil, storageCategory.ErrCategoryNotFound
+	}
+
+	return &stored, nil
+}
+
+func (s *Storage) store(ctx context.Context, category *model.Category) error {
+	if _, ok := s.categories[category.CategoryID]; ok {
+		return storageCategory.ErrCategoryExists
+	}
+
+	category.Version = 1
+	s.categories[category.CategoryID] = *category
+	s.addRevision(ctx, category)
+
+	return nil
+}
+
+func (s *Storage) update(ctx context.Context, category *model.Category, version int64, columns []string) error {
+	stored, ok := s.categories[category.CategoryID]
+	if !ok || stored.DeletedAt != nil {
+		return storageCategory.ErrCategoryNotFound
+	}
+
+	if stored.Version != version {
+		return storageCategory.ErrVersionConflict
+	}
+
+	for _, column := range columns {
+		switch column {
+		case storageCategory.ColumnName:
+			stored.Name = category.Name
+		case storageCategory.ColumnTitle:
+			stored.Title = category.Title
+		default:
+			return storageCategory.ErrUpdatingCategory
+		}
+	}
+
+	stored.Version = version + 1
+	s.categories[category.CategoryID] = stored
+	s.addRevision(ctx, &stored)
+
+	category.Version = stored.Version
+
+	return nil
+}
+
+func (s *Storage) discard(ID string) error {
+	stored, ok := s.categories[ID]
+	if !ok || stored.DeletedAt != nil {
+		return storageCategory.ErrCategoryNotFound
+	}
+
+	deletedAt := time.Now()
+	stored.DeletedAt = &deletedAt
+	s.categories[ID] = stored
+
+	return nil
+}
+
+func (s *Storage) addRevision(ctx context.Context, category *model.Category) {
+	s.revisions[category.CategoryID] = append(s.revisions[category.CategoryID], model.CategoryRevision{
+		CategoryID: category.CategoryID,
+		Revision:   category.Version,
+		Name:       category.Name,
+		Title:      category.Title,
+		Actor:      appContext.Actor(ctx),
+		CreatedAt:  time.Now(),
+	})
+}
+
+// snapshot copies the stored categories and revisions, so that a failed batch can be undone
+func (s *Storage) snapshot() *Storage {
+	snapshot := New()
+
+	for ID, category := range s.categories {
+		snapshot.categories[ID] = category
+	}
+	for ID, revisions := range s.revisions {
+		snapshot.revisions[ID] = append([]model.CategoryRevision(nil), revisions...)
+	}
+
+	return snapshot
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	storageCategory "github.com/gregbiv/news-api/pkg/storage/category"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("It stores and gets a category at its first version", func(t *testing.T) {
+		s := New()
+		category := &model.Category{CategoryID: "1", Name: "sport", Title: "Sport"}
+
+		assert.NoError(t, s.Store(ctx, category))
+		assert.Equal(t, int64(1), category.Version)
+
+		stored, err := s.GetCategoryByID(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, category, stored)
+
+		assert.Equal(t, storageCategory.ErrCategoryExists, s.Store(ctx, &model.Category{CategoryID: "1"}))
+	})
+
+	t.Run("It updates a category at the expected version only", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Store(ctx, &model.Category{CategoryID: "1", Name: "sport", Title: "Sport"}))
+
+		update := &model.Category{CategoryID: "1", Name: "sports", Title: "Sports"}
+		assert.NoError(t, s.UpdateColumns(ctx, update, 1, []string{storageCategory.ColumnTitle}))
+		assert.Equal(t, int64(2), update.Version)
+
+		stored, _ := s.GetCategoryByID(ctx, "1")
+		assert.Equal(t, "sport", stored.Name)
+		assert.Equal(t, "Sports", stored.Title)
+
+		assert.Equal(t, storageCategory.ErrVersionConflict, s.Update(ctx, update, 1))
+		assert.Equal(t, storageCategory.ErrCategoryNotFound, s.Update(ctx, &model.Category{CategoryID: "2"}, 1))
+
+		revisions, err := s.ListRevisions(ctx, "1", nil, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, revisions, 2) {
+			assert.Equal(t, int64(2), revisions[0].Revision)
+			assert.Equal(t, int64(1), revisions[1].Revision)
+		}
+	})
+
+	t.Run("It discards and restores a category", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Store(ctx, &model.Category{CategoryID: "1", Name: "sport", Title: "Sport"}))
+
+		assert.NoError(t, s.Discard(ctx, "1"))
+		assert.Equal(t, storageCategory.ErrCategoryNotFound, s.Discard(ctx, "1"))
+
+		_, err := s.GetCategoryByID(ctx, "1")
+		assert.Equal(t, storageCategory.ErrCategoryNotFound, err)
+
+		discarded, err := s.GetCategoryByIDIncludingDeleted(ctx, "1")
+		assert.NoError(t, err)
+		assert.NotNil(t, discarded.DeletedAt)
+
+		exists, err := s.AssertExists(ctx, "1")
+		assert.NoError(t, err)
+		assert.False(t, exists)
+
+		assert.NoError(t, s.Restore(ctx, "1"))
+		exists, _ = s.AssertExists(ctx, "1")
+		assert.True(t, exists)
+	})
+
+	t.Run("It applies all the operations of a batch or none of them", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Store(ctx, &model.Category{CategoryID: "1", Name: "sport", Title: "Sport"}))
+
+		update := &model.Category{CategoryID: "1", Name: "sports", Title: "Sports"}
+		err := s.Batch(ctx, []storageCategory.Operation{
+			{Op: storageCategory.OperationCreate, Category: &model.Category{CategoryID: "2", Name: "news", Title: "News"}},
+			{Op: storageCategory.OperationUpdate, Category: update, Version: 1},
+			{Op: storageCategory.OperationDelete, Category: &model.Category{CategoryID: "3"}},
+		})
+
+		if assert.IsType(t, &storageCategory.BatchError{}, err) {
+			assert.Equal(t, 2, err.(*storageCategory.BatchError).Index)
+			assert.Equal(t, storageCategory.ErrCategoryNotFound, err.(*storageCategory.BatchError).Err)
+		}
+		assert.Equal(t, int64(0), update.Version)
+
+		_, err = s.GetCategoryByID(ctx, "2")
+		assert.Equal(t, storageCategory.ErrCategoryNotFound, err)
+
+		stored, _ := s.GetCategoryByID(ctx, "1")
+		assert.Equal(t, "sport", stored.Name)
+		assert.Equal(t, int64(1), stored.Version)
+	})
+
+	t.Run("It undoes the changes of a unit of work that fails", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Store(ctx, &model.Category{CategoryID: "1", Name: "sport", Title: "Sport"}))
+
+		err := s.InTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			assert.NoError(t, s.Update(ctx, &model.Category{CategoryID: "1", Name: "sports", Title: "Sports"}, 1))
+			return storageCategory.ErrVersionConflict
+		})
+		assert.Equal(t, storageCategory.ErrVersionConflict, err)
+
+		stored, _ := s.GetCategoryByID(ctx, "1")
+		assert.Equal(t, "sport", stored.Name)
+		assert.Equal(t, int64(1), stored.Version)
+
+		revisions, _ := s.ListRevisions(ctx, "1", nil, nil)
+		assert.Len(t, revisions, 1)
+	})
+
+	t.Run("It is safe for concurrent use", func(t *testing.T) {
+		s := New()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ID := fmt.Sprintf("%d", i)
+				assert.NoError(t, s.Store(ctx, &model.Category{CategoryID: ID}))
+				_, err := s.GetCategoryByID(ctx, ID)
+				assert.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Len(t, s.categories, 10)
+	})
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// FieldChange describes how a single field of a resource changed
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff compares the JSON representations of two resources field by field and returns the changed fields.
+// A nil resource stands for one that does not exist, i.e. the before of a creation.
+func Diff(before, after interface{}) (map[string]FieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]FieldChange)
+	for field, value := range beforeFields {
+		if afterValue, ok := afterFields[field]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changes[field] = FieldChange{Before: value, After: afterFields[field]}
+		}
+	}
+
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = FieldChange{Before: nil, After: value}
+		}
+	}
+
+	return changes, nil
+}
+
+func jsonFields(resource interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if resource == nil || (reflect.ValueOf(resource).Kind() == reflect.Ptr && reflect.ValueOf(resource).IsNil()) {
+		return fields, nil
+	}
+
+	encoded, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type diffResource struct {
+	Name  string `json:"name"`
+	Title string `json:"title,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It lists every field of a created resource", func(t *testing.T) {
+		var before *diffResource
+
+		changes, err := Diff(before, &diffResource{Name: "business", Title: "Business"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]FieldChange{
+			"name":  {Before: nil, After: "business"},
+			"title": {Before: nil, After: "Business"},
+		}, changes)
+	})
+
+	t.Run("It only lists changed fields", func(t *testing.T) {
+		changes, err := Diff(
+			&diffResource{Name: "business", Title: "Business"},
+			&diffResource{Name: "business", Title: "Business news"},
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]FieldChange{
+			"title": {Before: "Business", After: "Business news"},
+		}, changes)
+	})
+
+	t.Run("It lists removed fields", func(t *testing.T) {
+		changes, err := Diff(&diffResource{Name: "business", Title: "Business"}, &diffResource{Name: "business"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]FieldChange{
+			"title": {Before: "Business", After: nil},
+		}, changes)
+	})
+
+	t.Run("It returns no changes for equal resources", func(t *testing.T) {
+		changes, err := Diff(&diffResource{Name: "business"}, &diffResource{Name: "business"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/palantir/stacktrace"
+)
+
+// Kind classifies the errors of the storages, whatever the resource and the database they come from
+type Kind int
+
+const (
+	// Unknown is the kind of the unexpected errors
+	Unknown Kind = iota
+	// NotFound is the kind of the errors caused by a resource that does not exist
+	NotFound
+	// Conflict is the kind of the errors caused by a change conflicting with the current state of a resource
+	Conflict
+	// Unavailable is the kind of the errors caused by the database being unreachable or overloaded
+	Unavailable
+	// Timeout is the kind of the errors caused by the database not answering in time
+	Timeout
+	// Constraint is the kind of the errors caused by a change breaking a constraint of a field
+	Constraint
+)
+
+// Error is an error of a known kind. Field names the field of the resource the error is about, if any.
+type Error struct {
+	Kind    Kind
+	Field   string
+	Message string
+	Err     error
+}
+
+// NewError inits and returns an Error of the given kind
+func NewError(kind Kind, field, message string) *Error {
+	return &Error{Kind: kind, Field: field, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return e.Message
+}
+
+// AsError returns the Error the given error was caused by. The errors of the
+// database drivers are classified, the other ones are of the Unknown kind.
+func AsError(err error) *Error {
+	cause := stacktrace.RootCause(err)
+	if storageErr, ok := cause.(*Error); ok {
+		return storageErr
+	}
+
+	if cause == context.DeadlineExceeded || database.IsQueryCanceled(cause) {
+		return &Error{Kind: Timeout, Message: "The database did not answer in time", Err: err}
+	}
+
+	if database.IsUnavailable(cause) {
+		return &Error{Kind: Unavailable, Message: "The database is unavailable", Err: err}
+	}
+
+	if column, ok := database.ConstraintViolation(cause); ok {
+		// The value a unique violation is about is valid, it conflicts with the value of another resource
+		if database.IsUniqueViolation(cause) {
+			return &Error{Kind: Conflict, Field: column, Message: "The value is already taken", Err: err}
+		}
+
+		return &Error{Kind: Constraint, Field: column, Message: "The value breaks a constraint of the field", Err: err}
+	}
+
+	return &Error{Kind: Unknown, Err: err}
+}
+
+// KindOf returns the kind of the given error
+func KindOf(err error) Kind {
+	return AsError(err).Kind
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/palantir/stacktrace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It returns the storage error the error was caused by", func(t *testing.T) {
+		notFound := NewError(NotFound, "category_id", "Unknown category")
+
+		assert.Equal(t, notFound, AsError(stacktrace.Propagate(notFound, "Failed to discard category")))
+	})
+
+	t.Run("It classifies the timeouts", func(t *testing.T) {
+		assert.Equal(t, Timeout, KindOf(context.DeadlineExceeded))
+		assert.Equal(t, Timeout, KindOf(&pq.Error{Code: "57014"}))
+	})
+
+	t.Run("It classifies the unavailable databases", func(t *testing.T) {
+		assert.Equal(t, Unavailable, KindOf(&pq.Error{Code: "08006"}))
+		assert.Equal(t, Unavailable, KindOf(&pq.Error{Code: "57P01"}))
+	})
+
+	t.Run("It classifies the constraint violations along with their column", func(t *testing.T) {
+		err := AsError(&pq.Error{Code: "23514", Column: "title"})
+
+		assert.Equal(t, Constraint, err.Kind)
+		assert.Equal(t, "title", err.Field)
+
+		assert.Equal(t, Constraint, KindOf(&pq.Error{Code: "23502", Column: "name"}))
+		assert.Equal(t, Constraint, KindOf(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintNotNull}))
+	})
+
+	t.Run("It classifies the unique violations as conflicts along with their column", func(t *testing.T) {
+		err := AsError(&pq.Error{Code: "23505", Detail: "Key (name)=(business) already exists."})
+
+		assert.Equal(t, Conflict, err.Kind)
+		assert.Equal(t, "name", err.Field)
+
+		assert.Equal(t, Conflict, KindOf(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}))
+	})
+
+	t.Run("It keeps the other errors unknown", func(t *testing.T) {
+		cause := errors.New("unexpected")
+		err := AsError(cause)
+
+		assert.Equal(t, Unknown, err.Kind)
+		assert.Equal(t, "unexpected", err.Error())
+	})
+}
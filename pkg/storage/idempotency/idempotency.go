@@ -0,0 +1,45 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+)
+
+var (
+	// ErrKeyMismatch is being returned when the key was used for a different request
+	ErrKeyMismatch = storage.NewError(
+		storage.Constraint,
+		"key",
+		"The idempotency key was already used for a different request",
+	)
+	// ErrInProgress is being returned when the request holding the key did not complete yet, nor ran out of its lease
+	ErrInProgress = storage.NewError(
+		storage.Conflict,
+		"key",
+		"A request with the same idempotency key is still being processed",
+	)
+)
+
+type (
+	// Store keeps the responses of the requests made with an idempotency key.
+	// Keys are scoped, so that clients cannot collide with each other.
+	Store interface {
+		// Begin claims the key for the request with the given hash. It returns the stored response
+		// when the request was already completed, and nothing when the caller should process it.
+		// The key is locked for the lease, an identical request may take it over once the lease ran
+		// out without the response being stored, i.e. when the instance processing it crashed.
+		Begin(ctx context.Context, scope, key, requestHash string, ttl, lease time.Duration) (*model.IdempotentResponse, error)
+		// Complete stores the response of the request holding the key
+		Complete(ctx context.Context, scope, key string, response *model.IdempotentResponse) error
+		// Release gives the key up, so that the request can be retried
+		Release(ctx context.Context, scope, key string) error
+	}
+
+	// Expirer is the object responsible for removing the expired keys
+	Expirer interface {
+		DeleteExpired(ctx context.Context) (int64, error)
+	}
+)
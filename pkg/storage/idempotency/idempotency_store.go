@@ -0,0 +1,153 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/palantir/stacktrace"
+)
+
+type (
+	dbStore struct {
+		db *sqlx.DB
+	}
+
+	// key describes an idempotency_key db model
+	key struct {
+		RequestHash    string        `db:"request_hash"`
+		StatusCode     sql.NullInt64 `db:"status_code"`
+		ResponseHeader []byte        `db:"response_header"`
+		ResponseBody   []byte        `db:"response_body"`
+	}
+)
+
+// NewStore inits and returns an instance of idempotency Store
+func NewStore(db *sqlx.DB) Store {
+	return &dbStore{db: db}
+}
+
+// NewExpirer inits and returns an instance of idempotency Expirer
+func NewExpirer(db *sqlx.DB) Expirer {
+	return &dbStore{db: db}
+}
+
+func (s *dbStore) Begin(
+	ctx context.Context,
+	scope string,
+	idempotencyKey string,
+	requestHash string,
+	ttl time.Duration,
+	lease time.Duration,
+) (*model.IdempotentResponse, error) {
+	// Expired keys are claimed again as if they never existed, and the keys of the identical
+	// requests that ran out of their lease without completing are taken over
+	claim := `
+		INSERT INTO idempotency_key AS k (scope, idempotency_key, request_hash, locked_until, expires_at)
+			VALUES ($1, $2, $3, now() + $4 * interval '1 millisecond', now() + $5 * interval '1 millisecond')
+			ON CONFLICT (scope, idempotency_key) DO UPDATE
+				SET
+					request_hash = EXCLUDED.request_hash,
+					status_code = NULL,
+					response_header = NULL,
+					response_body = NULL,
+					created_at = now(),
+					locked_until = EXCLUDED.locked_until,
+					expires_at = EXCLUDED.expires_at
+				WHERE k.expires_at < now()
+					OR (k.status_code IS NULL AND k.locked_until < now() AND k.request_hash = EXCLUDED.request_hash)
+			RETURNING idempotency_key`
+
+	var claimed string
+	err := database.Conn(ctx, s.db).QueryRowxContext(ctx, claim, scope, idempotencyKey, requestHash, milliseconds(lease), milliseconds(ttl)).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, stacktrace.Propagate(err, "failed to claim the idempotency key")
+	}
+
+	query := `
+		SELECT
+			request_hash,
+			status_code,
+			response_header,
+			response_body
+		FROM
+			idempotency_key
+		WHERE
+			scope = $1
+			AND idempotency_key = $2`
+
+	var stored key
+	if err := sqlx.GetContext(ctx, database.Conn(ctx, s.db), &stored, query, scope, idempotencyKey); err != nil {
+		// The key was released in between, the client may retry
+		if err == sql.ErrNoRows {
+			return nil, ErrInProgress
+		}
+		return nil, err
+	}
+
+	if stored.RequestHash != requestHash {
+		return nil, ErrKeyMismatch
+	}
+
+	if !stored.StatusCode.Valid {
+		return nil, ErrInProgress
+	}
+
+	response := &model.IdempotentResponse{
+		StatusCode: int(stored.StatusCode.Int64),
+		Body:       stored.ResponseBody,
+	}
+
+	if err := json.Unmarshal(stored.ResponseHeader, &response.Header); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *dbStore) Complete(ctx context.Context, scope, idempotencyKey string, response *model.IdempotentResponse) error {
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE idempotency_key
+			SET status_code = $3, response_header = $4, response_body = $5, locked_until = NULL
+			WHERE scope = $1
+				AND idempotency_key = $2`
+
+	_, err = database.Conn(ctx, s.db).ExecContext(ctx, query, scope, idempotencyKey, response.StatusCode, header, response.Body)
+
+	return stacktrace.Propagate(err, "failed to store the idempotent response")
+}
+
+func (s *dbStore) Release(ctx context.Context, scope, idempotencyKey string) error {
+	_, err := database.Conn(ctx, s.db).ExecContext(ctx,
+		`DELETE FROM idempotency_key WHERE scope = $1 AND idempotency_key = $2 AND status_code IS NULL`,
+		scope,
+		idempotencyKey,
+	)
+
+	return stacktrace.Propagate(err, "failed to release the idempotency key")
+}
+
+func (s *dbStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := database.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM idempotency_key WHERE expires_at < now()`)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "failed to delete the expired idempotency keys")
+	}
+
+	return result.RowsAffected()
+}
+
+func milliseconds(d time.Duration) int64 {
+	return d.Nanoseconds() / int64(time.Millisecond)
+}
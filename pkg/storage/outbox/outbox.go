@@ -0,0 +1,222 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+)
+
+// Channel is the Postgres notification channel on which the ID of every committed event is published.
+// The notifications are sent by the outbox_event_commit trigger, in the order of the commit sequence.
+const Channel = "outbox_event"
+
+// ErrEventNotFound is being returned when the requested event does not exist
+var ErrEventNotFound = storage.NewError(storage.NotFound, "event_id", "Unknown event")
+
+type (
+	// Writer is the object responsible for writing events to the outbox.
+	// Events are written within the transaction of the change they describe,
+	// so they are only published once the change is committed.
+	Writer interface {
+		Write(ctx context.Context, tx *sqlx.Tx, event *model.Event) error
+	}
+
+	// Reader is the object responsible for reading the published events
+	Reader interface {
+		GetEvent(ctx context.Context, ID int64) (*model.Event, error)
+		// ListSince lists at most limit events committed after the event of the given sequence,
+		// in commit order and optionally filtered by type
+		ListSince(ctx context.Context, afterSequence int64, types []string, limit int) ([]*model.Event, error)
+	}
+
+	// Pruner is the object responsible for removing the events that were handled a while ago
+	Pruner interface {
+		Prune(ctx context.Context, before time.Time) (int64, error)
+	}
+
+	dbWriter struct{}
+
+	dbReader struct {
+		db *sqlx.DB
+	}
+
+	dbPruner struct {
+		db *sqlx.DB
+	}
+
+	// event describes an outbox_event db model
+	event struct {
+		EventID      int64     `db:"event_id"`
+		CommitSeq    int64     `db:"commit_seq"`
+		EventType    string    `db:"event_type"`
+		ResourceType string    `db:"resource_type"`
+		ResourceID   string    `db:"resource_id"`
+		Payload      []byte    `db:"payload"`
+		CreatedAt    time.Time `db:"created_at"`
+	}
+)
+
+// NewWriter inits and returns an instance of outbox Writer
+func NewWriter() Writer {
+	return &dbWriter{}
+}
+
+// NewReader inits and returns an instance of outbox Reader
+func NewReader(db *sqlx.DB) Reader {
+	return &dbReader{db: db}
+}
+
+// NewPruner inits and returns an instance of outbox Pruner
+func NewPruner(db *sqlx.DB) Pruner {
+	return &dbPruner{db: db}
+}
+
+// NewEvent builds an event of the given type carrying the JSON representation of the resource
+func NewEvent(eventType, resourceType, resourceID string, resource interface{}) (*model.Event, error) {
+	payload, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Event{
+		Type:         eventType,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Payload:      payload,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// Write stores the event within the transaction of the change. Nothing reads the events back
+// from SQLite, the dispatcher and the event streams need Postgres, so none are stored there.
+func (dw *dbWriter) Write(ctx context.Context, tx *sqlx.Tx, event *model.Event) error {
+	if tx.DriverName() == database.SQLite {
+		return nil
+	}
+
+	query := `
+        INSERT INTO outbox_event
+        (
+            event_type,
+            resource_type,
+            resource_id,
+            payload,
+            created_at
+        )
+        VALUES (?, ?, ?, ?, ?)
+    `
+
+	ID, err := database.InsertID(ctx,
+		tx,
+		query,
+		"event_id",
+		event.Type,
+		event.ResourceType,
+		event.ResourceID,
+		[]byte(event.Payload),
+		event.CreatedAt,
+	)
+	event.ID = ID
+
+	return stacktrace.Propagate(err, "failed to store data into outbox_event table")
+}
+
+func (dr *dbReader) GetEvent(ctx context.Context, ID int64) (*model.Event, error) {
+	query := `
+        SELECT
+            event_id,
+            COALESCE(commit_seq, 0) AS commit_seq,
+            event_type,
+            resource_type,
+            resource_id,
+            payload,
+            created_at
+        FROM
+            outbox_event
+        WHERE
+            event_id = $1
+    `
+
+	var dbEvent event
+	if err := sqlx.GetContext(ctx, database.Conn(ctx, dr.db), &dbEvent, query, ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+
+	return dbEvent.toModel(), nil
+}
+
+func (dr *dbReader) ListSince(ctx context.Context, afterSequence int64, types []string, limit int) ([]*model.Event, error) {
+	query := `
+        SELECT
+            event_id,
+            COALESCE(commit_seq, 0) AS commit_seq,
+            event_type,
+            resource_type,
+            resource_id,
+            payload,
+            created_at
+        FROM
+            outbox_event
+        WHERE
+            commit_seq > $1
+            AND (cardinality($2::text[]) = 0 OR event_type = ANY ($2))
+        ORDER BY commit_seq
+        LIMIT $3
+    `
+
+	var dbEvents []event
+	if err := sqlx.SelectContext(ctx, database.Conn(ctx, dr.db), &dbEvents, query, afterSequence, pq.StringArray(types), limit); err != nil {
+		return nil, err
+	}
+
+	events := make([]*model.Event, 0, len(dbEvents))
+	for i := range dbEvents {
+		events = append(events, dbEvents[i].toModel())
+	}
+
+	return events, nil
+}
+
+// Prune deletes the events created before the given time once the webhook dispatcher is done with them
+func (dp *dbPruner) Prune(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+        DELETE FROM outbox_event e
+        WHERE
+            created_at < $1
+            AND dispatched_at IS NOT NULL
+            AND NOT EXISTS (
+                SELECT 1
+                FROM webhook_delivery d
+                WHERE d.event_id = e.event_id AND d.status = 'pending'
+            )
+    `
+	result, err := database.Conn(ctx, dp.db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "failed to prune the outbox events created before %s", before)
+	}
+
+	return result.RowsAffected()
+}
+
+func (e *event) toModel() *model.Event {
+	return &model.Event{
+		ID:           e.EventID,
+		Sequence:     e.CommitSeq,
+		Type:         e.EventType,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		Payload:      e.Payload,
+		CreatedAt:    e.CreatedAt,
+	}
+}
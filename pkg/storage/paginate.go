@@ -0,0 +1,26 @@
+package storage
+
+const (
+	// DefaultTop is the page size used when the client does not ask for one
+	DefaultTop uint64 = 20
+	// MaxTop is the biggest page size a client can ask for
+	MaxTop uint64 = 100
+)
+
+// OffsetLimit turns the optional $skip and $top pagination parameters into an SQL offset and limit
+func OffsetLimit(skip, top *uint64) (offset, limit uint64) {
+	limit = DefaultTop
+
+	if skip != nil {
+		offset = *skip
+	}
+
+	if top != nil {
+		limit = *top
+		if limit > MaxTop {
+			limit = MaxTop
+		}
+	}
+
+	return offset, limit
+}
@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrWebhookNotFound is being returned when the requested webhook does not exist
+	ErrWebhookNotFound = storage.NewError(storage.NotFound, "webhook_id", "Unknown webhook")
+	// ErrDeliveryNotFound is being returned when the requested delivery does not exist
+	ErrDeliveryNotFound = storage.NewError(storage.NotFound, "delivery_id", "Unknown webhook delivery")
+)
+
+type (
+	// Storer is the object responsible for storing webhooks
+	Storer interface {
+		Store(ctx context.Context, webhook *model.Webhook) error
+	}
+
+	// Getter is the object responsible for retrieving webhooks
+	Getter interface {
+		GetWebhookByID(ctx context.Context, ID string) (*model.Webhook, error)
+	}
+
+	// Lister is the object responsible for listing webhooks
+	Lister interface {
+		List(ctx context.Context, skip, top *uint64) ([]*model.Webhook, error)
+	}
+
+	// Updater is the object responsible for updating webhooks
+	Updater interface {
+		// Update replaces the subscription, an empty secret keeps the current one
+		Update(ctx context.Context, webhook *model.Webhook) error
+	}
+
+	// Deleter is the object responsible for removing webhooks along with their deliveries
+	Deleter interface {
+		Delete(ctx context.Context, ID string) error
+	}
+
+	// AttemptRecorder is the object responsible for logging the requests sent to the subscribers
+	AttemptRecorder interface {
+		RecordAttempt(ctx context.Context, attempt *model.WebhookAttempt) error
+	}
+
+	// AttemptLister is the object responsible for listing the delivery log of a webhook
+	AttemptLister interface {
+		ListAttempts(ctx context.Context, webhookID string, skip, top *uint64) ([]*model.WebhookAttempt, error)
+	}
+
+	// webhookManager handles the
+	// webhook subscriptions
+	webhookManager struct {
+		db *sqlx.DB
+	}
+
+	// webhook describes a webhook db model
+	webhook struct {
+		WebhookID  string         `db:"webhook_id"`
+		TargetURL  string         `db:"target_url"`
+		EventTypes pq.StringArray `db:"event_types"`
+		Secret     string         `db:"secret"`
+		Active     bool           `db:"active"`
+		CreatedAt  time.Time      `db:"created_at"`
+	}
+
+	// attempt describes a webhook_attempt db model
+	attempt struct {
+		AttemptID       int64         `db:"attempt_id"`
+		WebhookID       string        `db:"webhook_id"`
+		DeliveryID      sql.NullInt64 `db:"delivery_id"`
+		EventType       string        `db:"event_type"`
+		StatusCode      sql.NullInt64 `db:"status_code"`
+		Latency         int64         `db:"latency_ms"`
+		ResponseExcerpt string        `db:"response_excerpt"`
+		Error           string        `db:"error"`
+		CreatedAt       time.Time     `db:"created_at"`
+	}
+
+	// Scheduler fans the outbox events out into
+	// deliveries for the subscribed webhooks
+	Scheduler interface {
+		// Schedule schedules the deliveries of at most limit pending events and returns how many were handled
+		Schedule(ctx context.Context, limit int) (int64, error)
+	}
+
+	// Claimer claims the deliveries that are due, so that
+	// concurrent dispatchers do not attempt the same delivery
+	Claimer interface {
+		// Claim claims at most limit due deliveries for the lease duration and counts the attempt
+		Claim(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error)
+	}
+
+	// Resolver stores the outcome of a delivery attempt
+	Resolver interface {
+		// Delivered marks the delivery as acknowledged by the subscriber
+		Delivered(ctx context.Context, ID int64) error
+		// Failed reschedules the delivery for another attempt
+		Failed(ctx context.Context, ID int64, reason string, retryAt time.Time) error
+		// Dead moves the delivery to the dead letters, it is not attempted anymore
+		Dead(ctx context.Context, ID int64, reason string) error
+	}
+
+	// DeliveryLister is the object responsible for listing deliveries
+	DeliveryLister interface {
+		// ListByStatus lists the deliveries with the given status, newest first
+		ListByStatus(ctx context.Context, status string, skip, top *uint64) ([]*model.WebhookDelivery, error)
+	}
+
+	// Requeuer is the object responsible for giving dead deliveries another chance
+	Requeuer interface {
+		Requeue(ctx context.Context, ID int64) error
+	}
+
+	// deliveryManager handles the
+	// lifecycle of webhook deliveries
+	deliveryManager struct {
+		db *sqlx.DB
+	}
+
+	// delivery describes a webhook_delivery db model
+	delivery struct {
+		DeliveryID    int64     `db:"delivery_id"`
+		WebhookID     string    `db:"webhook_id"`
+		EventID       int64     `db:"event_id"`
+		Status        string    `db:"status"`
+		Attempts      int       `db:"attempts"`
+		NextAttemptAt time.Time `db:"next_attempt_at"`
+		LastError     string    `db:"last_error"`
+		CreatedAt     time.Time `db:"created_at"`
+		UpdatedAt     time.Time `db:"updated_at"`
+	}
+
+	// claimedDelivery describes a delivery joined with its webhook and event
+	claimedDelivery struct {
+		delivery
+		TargetURL      string    `db:"target_url"`
+		Secret         string    `db:"secret"`
+		EventType      string    `db:"event_type"`
+		ResourceType   string    `db:"resource_type"`
+		ResourceID     string    `db:"resource_id"`
+		Payload        []byte    `db:"payload"`
+		EventCreatedAt time.Time `db:"event_created_at"`
+	}
+)
+
+func newWebhookManager(db *sqlx.DB) *webhookManager {
+	return &webhookManager{db: db}
+}
+
+func newDeliveryManager(db *sqlx.DB) *deliveryManager {
+	return &deliveryManager{db: db}
+}
+
+func (w *webhook) toModel() *model.Webhook {
+	return &model.Webhook{
+		WebhookID:  w.WebhookID,
+		TargetURL:  w.TargetURL,
+		EventTypes: []string(w.EventTypes),
+		Secret:     w.Secret,
+		Active:     w.Active,
+		CreatedAt:  w.CreatedAt,
+	}
+}
+
+func (a *attempt) toModel() *model.WebhookAttempt {
+	modelAttempt := &model.WebhookAttempt{
+		AttemptID:       a.AttemptID,
+		WebhookID:       a.WebhookID,
+		EventType:       a.EventType,
+		Latency:         a.Latency,
+		ResponseExcerpt: a.ResponseExcerpt,
+		Error:           a.Error,
+		CreatedAt:       a.CreatedAt,
+	}
+
+	if a.DeliveryID.Valid {
+		deliveryID := a.DeliveryID.Int64
+		modelAttempt.DeliveryID = &deliveryID
+	}
+
+	if a.StatusCode.Valid {
+		statusCode := int(a.StatusCode.Int64)
+		modelAttempt.StatusCode = &statusCode
+	}
+
+	return modelAttempt
+}
+
+func (d *delivery) toModel() *model.WebhookDelivery {
+	return &model.WebhookDelivery{
+		DeliveryID:    d.DeliveryID,
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		LastError:     d.LastError,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+}
+
+func (d *claimedDelivery) toModel() *model.WebhookDelivery {
+	modelDelivery := d.delivery.toModel()
+	modelDelivery.Webhook = &model.Webhook{
+		WebhookID: d.WebhookID,
+		TargetURL: d.TargetURL,
+		Secret:    d.Secret,
+		Active:    true,
+	}
+	modelDelivery.Event = &model.Event{
+		ID:           d.EventID,
+		Type:         d.EventType,
+		ResourceType: d.ResourceType,
+		ResourceID:   d.ResourceID,
+		Payload:      d.Payload,
+		CreatedAt:    d.EventCreatedAt,
+	}
+
+	return modelDelivery
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/palantir/stacktrace"
+)
+
+// NewAttemptRecorder inits and returns an instance of AttemptRecorder
+func NewAttemptRecorder(db *sqlx.DB) AttemptRecorder {
+	return newWebhookManager(db)
+}
+
+// NewAttemptLister inits and returns an instance of AttemptLister
+func NewAttemptLister(db *sqlx.DB) AttemptLister {
+	return newWebhookManager(db)
+}
+
+func (m *webhookManager) RecordAttempt(ctx context.Context, a *model.WebhookAttempt) error {
+	query := `
+        INSERT INTO webhook_attempt
+        (
+            webhook_id,
+            delivery_id,
+            event_type,
+            status_code,
+            latency_ms,
+            response_excerpt,
+            error
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING attempt_id, created_at
+    `
+
+	err := database.Conn(ctx, m.db).QueryRowxContext(ctx,
+		query,
+		a.WebhookID,
+		a.DeliveryID,
+		a.EventType,
+		a.StatusCode,
+		a.Latency,
+		a.ResponseExcerpt,
+		a.Error,
+	).Scan(&a.AttemptID, &a.CreatedAt)
+
+	return stacktrace.Propagate(err, "failed to store data into webhook_attempt table")
+}
+
+func (m *webhookManager) ListAttempts(ctx context.Context, webhookID string, skip, top *uint64) ([]*model.WebhookAttempt, error) {
+	query := `
+		SELECT
+			attempt_id,
+			webhook_id,
+			delivery_id,
+			event_type,
+			status_code,
+			latency_ms,
+			response_excerpt,
+			error,
+			created_at
+		FROM
+			webhook_attempt
+		WHERE
+			webhook_id = $1
+		ORDER BY attempt_id DESC
+		OFFSET $2
+		LIMIT $3`
+
+	offset, limit := storage.OffsetLimit(skip, top)
+
+	var dbAttempts []attempt
+	if err := sqlx.SelectContext(ctx, database.Conn(ctx, m.db), &dbAttempts, query, webhookID, offset, limit); err != nil {
+		return nil, err
+	}
+
+	attempts := make([]*model.WebhookAttempt, 0, len(dbAttempts))
+	for i := range dbAttempts {
+		attempts = append(attempts, dbAttempts[i].toModel())
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/palantir/stacktrace"
+)
+
+// NewScheduler inits and returns an instance of delivery Scheduler
+func NewScheduler(db *sqlx.DB) Scheduler {
+	return newDeliveryManager(db)
+}
+
+// NewClaimer inits and returns an instance of delivery Claimer
+func NewClaimer(db *sqlx.DB) Claimer {
+	return newDeliveryManager(db)
+}
+
+// NewResolver inits and returns an instance of delivery Resolver
+func NewResolver(db *sqlx.DB) Resolver {
+	return newDeliveryManager(db)
+}
+
+// NewDeliveryLister inits and returns an instance of DeliveryLister
+func NewDeliveryLister(db *sqlx.DB) DeliveryLister {
+	return newDeliveryManager(db)
+}
+
+// NewRequeuer inits and returns an instance of delivery Requeuer
+func NewRequeuer(db *sqlx.DB) Requeuer {
+	return newDeliveryManager(db)
+}
+
+func (m *deliveryManager) Schedule(ctx context.Context, limit int) (int64, error) {
+	query := `
+		WITH events AS (
+			SELECT event_id, event_type
+			FROM outbox_event
+			WHERE dispatched_at IS NULL
+			ORDER BY event_id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), deliveries AS (
+			INSERT INTO webhook_delivery (webhook_id, event_id)
+			SELECT w.webhook_id, e.event_id
+			FROM events e
+			JOIN webhook w ON w.active AND e.event_type = ANY (w.event_types)
+			ON CONFLICT (webhook_id, event_id) DO NOTHING
+		)
+		UPDATE outbox_event
+			SET dispatched_at = now()
+			WHERE event_id IN (SELECT event_id FROM events)`
+
+	result, err := database.Conn(ctx, m.db).ExecContext(ctx, query, limit)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "failed to schedule webhook deliveries")
+	}
+
+	return result.RowsAffected()
+}
+
+func (m *deliveryManager) Claim(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT delivery_id
+			FROM webhook_delivery
+			WHERE status = 'pending'
+				AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_delivery d
+			SET
+				attempts = d.attempts + 1,
+				next_attempt_at = now() + $2 * interval '1 millisecond',
+				updated_at = now()
+			FROM due, webhook w, outbox_event e
+			WHERE d.delivery_id = due.delivery_id
+				AND w.webhook_id = d.webhook_id
+				AND e.event_id = d.event_id
+			RETURNING
+				d.delivery_id,
+				d.webhook_id,
+				d.event_id,
+				d.status,
+				d.attempts,
+				d.next_attempt_at,
+				d.last_error,
+				d.created_at,
+				d.updated_at,
+				w.target_url,
+				w.secret,
+				e.event_type,
+				e.resource_type,
+				e.resource_id,
+				e.payload,
+				e.created_at AS event_created_at`
+
+	var claimed []claimedDelivery
+	if err := sqlx.SelectContext(ctx, database.Conn(ctx, m.db), &claimed, query, limit, lease.Nanoseconds()/int64(time.Millisecond)); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to claim webhook deliveries")
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0, len(claimed))
+	for i := range claimed {
+		deliveries = append(deliveries, claimed[i].toModel())
+	}
+
+	return deliveries, nil
+}
+
+func (m *deliveryManager) Delivered(ctx context.Context, ID int64) error {
+	query := `
+		UPDATE webhook_delivery
+			SET status = $2, last_error = '', updated_at = now()
+			WHERE delivery_id = $1`
+
+	return m.executeSingleRow(ctx, query, ID, model.DeliveryDelivered)
+}
+
+func (m *deliveryManager) Failed(ctx context.Context, ID int64, reason string, retryAt time.Time) error {
+	query := `
+		UPDATE webhook_delivery
+			SET last_error = $2, next_attempt_at = $3, updated_at = now()
+			WHERE delivery_id = $1`
+
+	return m.executeSingleRow(ctx, query, ID, reason, retryAt)
+}
+
+func (m *deliveryManager) Dead(ctx context.Context, ID int64, reason string) error {
+	query := `
+		UPDATE webhook_delivery
+			SET status = $2, last_error = $3, updated_at = now()
+			WHERE delivery_id = $1`
+
+	return m.executeSingleRow(ctx, query, ID, model.DeliveryDead, reason)
+}
+
+func (m *deliveryManager) ListByStatus(ctx context.Context, status string, skip, top *uint64) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT
+			delivery_id,
+			webhook_id,
+			event_id,
+			status,
+			attempts,
+			next_attempt_at,
+			last_error,
+			created_at,
+			updated_at
+		FROM
+			webhook_delivery
+		WHERE
+			status = $1
+		ORDER BY delivery_id DESC
+		OFFSET $2
+		LIMIT $3`
+
+	offset, limit := storage.OffsetLimit(skip, top)
+
+	var dbDeliveries []delivery
+	if err := sqlx.SelectContext(ctx, database.Conn(ctx, m.db), &dbDeliveries, query, status, offset, limit); err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0, len(dbDeliveries))
+	for i := range dbDeliveries {
+		deliveries = append(deliveries, dbDeliveries[i].toModel())
+	}
+
+	return deliveries, nil
+}
+
+func (m *deliveryManager) Requeue(ctx context.Context, ID int64) error {
+	query := `
+		UPDATE webhook_delivery
+			SET status = $2, attempts = 0, next_attempt_at = now(), updated_at = now()
+			WHERE delivery_id = $1
+				AND status = $3`
+
+	return m.executeSingleRow(ctx, query, ID, model.DeliveryPending, model.DeliveryDead)
+}
+
+func (m *deliveryManager) executeSingleRow(ctx context.Context, query string, args ...interface{}) error {
+	result, err := database.Conn(ctx, m.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return affectedSingleRow(result, ErrDeliveryNotFound)
+}
+
+// affectedSingleRow returns notFound when the statement did not affect any row
+func affectedSingleRow(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewGetter inits and returns an instance of webhook Getter
+func NewGetter(db *sqlx.DB) Getter {
+	return newWebhookManager(db)
+}
+
+// NewLister inits and returns an instance of webhook Lister
+func NewLister(db *sqlx.DB) Lister {
+	return newWebhookManager(db)
+}
+
+func (m *webhookManager) GetWebhookByID(ctx context.Context, ID string) (*model.Webhook, error) {
+	query := `
+		SELECT
+			webhook_id,
+			target_url,
+			event_types,
+			secret,
+			active,
+			created_at
+		FROM
+			webhook
+		WHERE
+			webhook_id = $1`
+
+	var dbWebhook webhook
+	err := sqlx.GetContext(ctx, database.Conn(ctx, m.db), &dbWebhook, query, ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return dbWebhook.toModel(), nil
+}
+
+func (m *webhookManager) List(ctx context.Context, skip, top *uint64) ([]*model.Webhook, error) {
+	query := `
+		SELECT
+			webhook_id,
+			target_url,
+			event_types,
+			secret,
+			active,
+			created_at
+		FROM
+			webhook
+		ORDER BY created_at, webhook_id
+		OFFSET $1
+		LIMIT $2`
+
+	offset, limit := storage.OffsetLimit(skip, top)
+
+	var dbWebhooks []webhook
+	if err := sqlx.SelectContext(ctx, database.Conn(ctx, m.db), &dbWebhooks, query, offset, limit); err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]*model.Webhook, 0, len(dbWebhooks))
+	for i := range dbWebhooks {
+		webhooks = append(webhooks, dbWebhooks[i].toModel())
+	}
+
+	return webhooks, nil
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"github.com/gregbiv/news-api/pkg/database"
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+)
+
+// NewStorer inits and returns an instance of webhook Storer
+func NewStorer(db *sqlx.DB) Storer {
+	return newWebhookManager(db)
+}
+
+// NewUpdater inits and returns an instance of webhook Updater
+func NewUpdater(db *sqlx.DB) Updater {
+	return newWebhookManager(db)
+}
+
+// NewDeleter inits and returns an instance of webhook Deleter
+func NewDeleter(db *sqlx.DB) Deleter {
+	return newWebhookManager(db)
+}
+
+func (m *webhookManager) Store(ctx context.Context, w *model.Webhook) error {
+	query := `
+        INSERT INTO webhook
+        (
+            webhook_id,
+            target_url,
+            event_types,
+            secret,
+            active
+        )
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING created_at
+    `
+
+	err := database.Conn(ctx, m.db).QueryRowxContext(ctx,
+		query,
+		w.WebhookID,
+		w.TargetURL,
+		pq.StringArray(w.EventTypes),
+		w.Secret,
+		w.Active,
+	).Scan(&w.CreatedAt)
+
+	return stacktrace.Propagate(err, "failed to store data into webhook table")
+}
+
+func (m *webhookManager) Update(ctx context.Context, w *model.Webhook) error {
+	query := `
+        UPDATE webhook
+            SET
+                target_url = $2,
+                event_types = $3,
+                secret = COALESCE(NULLIF($4, ''), secret),
+                active = $5
+            WHERE webhook_id = $1
+    `
+
+	result, err := database.Conn(ctx, m.db).ExecContext(ctx,
+		query,
+		w.WebhookID,
+		w.TargetURL,
+		pq.StringArray(w.EventTypes),
+		w.Secret,
+		w.Active,
+	)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to update data in webhook table")
+	}
+
+	return affectedSingleRow(result, ErrWebhookNotFound)
+}
+
+func (m *webhookManager) Delete(ctx context.Context, ID string) error {
+	result, err := database.Conn(ctx, m.db).ExecContext(ctx, `DELETE FROM webhook WHERE webhook_id = $1`, ID)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to delete data from webhook table")
+	}
+
+	return affectedSingleRow(result, ErrWebhookNotFound)
+}
@@ -0,0 +1,28 @@
+package webhook
+
+import "time"
+
+const (
+	// BaseBackoff is the delay before the second attempt of a delivery
+	BaseBackoff = 10 * time.Second
+	// MaxBackoff caps the delay between two attempts of a delivery
+	MaxBackoff = time.Hour
+)
+
+// Backoff returns the delay to wait after the given (1-based) failed attempt,
+// doubling on every attempt up to MaxBackoff
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= MaxBackoff {
+			return MaxBackoff
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 0, expected: 10 * time.Second},
+		{attempt: 1, expected: 10 * time.Second},
+		{attempt: 2, expected: 20 * time.Second},
+		{attempt: 5, expected: 160 * time.Second},
+		{attempt: 9, expected: 2560 * time.Second},
+		{attempt: 10, expected: time.Hour},
+		{attempt: 100, expected: time.Hour},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Backoff(test.attempt), "attempt %d", test.attempt)
+	}
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	storageWebhook "github.com/gregbiv/news-api/pkg/storage/webhook"
+	log "github.com/sirupsen/logrus"
+)
+
+// deliveryMargin is the time the database writes following a delivery attempt may take
+const deliveryMargin = 5 * time.Second
+
+type (
+	// Options tunes the dispatcher
+	Options struct {
+		PollInterval time.Duration
+		BatchSize    int
+		Timeout      time.Duration
+		MaxAttempts  int
+	}
+
+	// Dispatcher moves the outbox events to the subscribed webhooks
+	Dispatcher struct {
+		scheduler storageWebhook.Scheduler
+		claimer   storageWebhook.Claimer
+		resolver  storageWebhook.Resolver
+		recorder  storageWebhook.AttemptRecorder
+		sender    Sender
+		options   Options
+	}
+)
+
+// NewDispatcher inits and returns an instance of Dispatcher
+func NewDispatcher(
+	scheduler storageWebhook.Scheduler,
+	claimer storageWebhook.Claimer,
+	resolver storageWebhook.Resolver,
+	recorder storageWebhook.AttemptRecorder,
+	sender Sender,
+	options Options,
+) *Dispatcher {
+	return &Dispatcher{
+		scheduler: scheduler,
+		claimer:   claimer,
+		resolver:  resolver,
+		recorder:  recorder,
+		sender:    sender,
+		options:   options,
+	}
+}
+
+// Run dispatches until the context is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Dispatch(ctx); err != nil {
+			log.Errorf("Dispatching webhooks failed: %+v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch schedules the pending events and attempts the due deliveries once
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	if _, err := d.scheduler.Schedule(ctx, d.options.BatchSize); err != nil {
+		return err
+	}
+
+	// The deliveries of a batch are sent one after another, so the lease covers all of them.
+	// It still expires, so that a crashed dispatcher does not keep them from being retried.
+	perDelivery := d.options.Timeout + deliveryMargin
+	leaseExpiry := time.Now().Add(time.Duration(d.options.BatchSize) * perDelivery)
+
+	deliveries, err := d.claimer.Claim(ctx, d.options.BatchSize, time.Until(leaseExpiry))
+	if err != nil {
+		return err
+	}
+
+	for i, delivery := range deliveries {
+		// Another dispatcher may claim the deliveries once the lease expired,
+		// the remaining ones are left to it rather than being sent twice
+		if time.Now().Add(perDelivery).After(leaseExpiry) {
+			log.Warnf("The lease of the webhook deliveries expires, %d deliveries are left to the next claim", len(deliveries)-i)
+			return nil
+		}
+
+		result := d.sender.Send(delivery)
+
+		logger := log.WithFields(log.Fields{
+			"delivery_id": delivery.DeliveryID,
+			"webhook_id":  delivery.WebhookID,
+			"event_id":    delivery.EventID,
+			"attempt":     delivery.Attempts,
+			"status_code": result.StatusCode,
+			"latency":     result.Latency.String(),
+		})
+
+		// The delivery log is informative only, failing to write it
+		// must not cause the subscriber to receive the event twice
+		if err := d.recorder.RecordAttempt(ctx, result.Attempt(delivery)); err != nil {
+			logger.Errorf("Recording the webhook attempt failed: %+v", err)
+		}
+
+		switch {
+		case result.OK():
+			err = d.resolver.Delivered(ctx, delivery.DeliveryID)
+			logger.Info("Webhook delivered")
+		case delivery.Attempts >= d.options.MaxAttempts:
+			err = d.resolver.Dead(ctx, delivery.DeliveryID, result.Reason())
+			logger.Warnf("Webhook delivery is dead: %s", result.Reason())
+		default:
+			err = d.resolver.Failed(ctx, delivery.DeliveryID, result.Reason(), time.Now().Add(Backoff(delivery.Attempts)))
+			logger.Infof("Webhook delivery failed: %s", result.Reason())
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	fakeQueue struct {
+		deliveries []*model.WebhookDelivery
+		lease      time.Duration
+		delivered  []int64
+	}
+
+	fakeSender struct {
+		sent []int64
+	}
+)
+
+func (q *fakeQueue) Schedule(ctx context.Context, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (q *fakeQueue) Claim(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	q.lease = lease
+	return q.deliveries, nil
+}
+
+func (q *fakeQueue) Delivered(ctx context.Context, ID int64) error {
+	q.delivered = append(q.delivered, ID)
+	return nil
+}
+
+func (q *fakeQueue) Failed(ctx context.Context, ID int64, reason string, retryAt time.Time) error {
+	return nil
+}
+
+func (q *fakeQueue) Dead(ctx context.Context, ID int64, reason string) error {
+	return nil
+}
+
+func (q *fakeQueue) RecordAttempt(ctx context.Context, attempt *model.WebhookAttempt) error {
+	return nil
+}
+
+func (s *fakeSender) Send(delivery *model.WebhookDelivery) Result {
+	s.sent = append(s.sent, delivery.DeliveryID)
+	return Result{StatusCode: 204}
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It leases the deliveries for as long as sending the whole batch may take", func(t *testing.T) {
+		queue := &fakeQueue{deliveries: []*model.WebhookDelivery{
+			{DeliveryID: 1, Webhook: &model.Webhook{}, Event: &model.Event{}},
+			{DeliveryID: 2, Webhook: &model.Webhook{}, Event: &model.Event{}},
+			{DeliveryID: 3, Webhook: &model.Webhook{}, Event: &model.Event{}},
+		}}
+		sender := &fakeSender{}
+
+		dispatcher := NewDispatcher(queue, queue, queue, queue, sender, Options{BatchSize: 3, Timeout: 10 * time.Second, MaxAttempts: 5})
+
+		assert.NoError(t, dispatcher.Dispatch(context.Background()))
+		assert.True(t, queue.lease >= 30*time.Second, "the lease of %s does not cover the batch", queue.lease)
+		assert.Equal(t, []int64{1, 2, 3}, sender.sent)
+		assert.Equal(t, []int64{1, 2, 3}, queue.delivered)
+	})
+}
@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/gregbiv/news-api/pkg/storage/outbox"
+)
+
+const (
+	// EventPing is the type of the event sent to test a subscription
+	EventPing = "ping"
+
+	resourceTypeWebhook = "webhook"
+)
+
+// NewPing returns a delivery of a ping event to the given webhook. Pings are
+// sent right away and bypass the outbox, they are not retried.
+func NewPing(webhook *model.Webhook) (*model.WebhookDelivery, error) {
+	event, err := outbox.NewEvent(EventPing, resourceTypeWebhook, webhook.WebhookID, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WebhookDelivery{
+		WebhookID: webhook.WebhookID,
+		Status:    model.DeliveryPending,
+		Attempts:  1,
+		CreatedAt: time.Now(),
+		Webhook:   webhook,
+		Event:     event,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/model"
+)
+
+// maxResponseExcerpt is the amount of the subscriber response that is kept for troubleshooting
+const maxResponseExcerpt = 1024
+
+type (
+	// Sender delivers events to the subscribed endpoints
+	Sender interface {
+		Send(delivery *model.WebhookDelivery) Result
+	}
+
+	// Result describes the outcome of a single delivery attempt
+	Result struct {
+		StatusCode int
+		Latency    time.Duration
+		Response   string
+		Err        error
+	}
+
+	httpSender struct {
+		client *http.Client
+	}
+)
+
+// NewSender inits and returns an instance of Sender using the given request timeout.
+// The requests are sent directly to the public addresses of the subscribers only.
+func NewSender(timeout time.Duration) Sender {
+	transport := &http.Transport{
+		DialContext:         dialPublic(&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}),
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	return &httpSender{
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// OK reports whether the subscriber acknowledged the delivery
+func (r Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Reason describes why the delivery attempt failed
+func (r Result) Reason() string {
+	if r.Err != nil {
+		return r.Err.Error()
+	}
+
+	return fmt.Sprintf("Unexpected status code %d", r.StatusCode)
+}
+
+// Attempt returns the delivery log entry of the result
+func (r Result) Attempt(delivery *model.WebhookDelivery) *model.WebhookAttempt {
+	attempt := &model.WebhookAttempt{
+		WebhookID:       delivery.Webhook.WebhookID,
+		EventType:       delivery.Event.Type,
+		Latency:         r.Latency.Nanoseconds() / int64(time.Millisecond),
+		ResponseExcerpt: r.Response,
+	}
+
+	if delivery.DeliveryID != 0 {
+		deliveryID := delivery.DeliveryID
+		attempt.DeliveryID = &deliveryID
+	}
+
+	if r.StatusCode != 0 {
+		statusCode := r.StatusCode
+		attempt.StatusCode = &statusCode
+	}
+
+	if !r.OK() {
+		attempt.Error = r.Reason()
+	}
+
+	return attempt
+}
+
+func (s *httpSender) Send(delivery *model.WebhookDelivery) Result {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, delivery.Webhook.TargetURL, bytes.NewReader(body))
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event.Type)
+	if delivery.DeliveryID != 0 {
+		req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.DeliveryID, 10))
+	}
+	now := time.Now()
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Webhook.Secret, now, body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Result{Latency: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+
+	excerpt, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseExcerpt))
+
+	return Result{
+		StatusCode: resp.StatusCode,
+		Latency:    time.Since(start),
+		Response:   string(excerpt),
+	}
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of the timestamp and the request body
+	SignatureHeader = "X-News-Signature"
+	// TimestampHeader carries the Unix time the request was signed at, receivers should
+	// reject the requests signed too long ago so that they cannot be replayed
+	TimestampHeader = "X-News-Timestamp"
+	// EventHeader carries the type of the delivered event
+	EventHeader = "X-News-Event"
+	// DeliveryHeader carries the identifier of the delivery, it is stable across retries
+	DeliveryHeader = "X-News-Delivery"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the signature of the body sent at the given time for the given secret, as sent in the
+// SignatureHeader. The signed payload is the TimestampHeader value and the body joined by a dot.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature matches the body and the TimestampHeader value for the given
+// secret, and whether the body was signed within the tolerance of the current time
+func Verify(secret string, body []byte, timestamp, signature string, tolerance time.Duration) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	signedAt := time.Unix(seconds, 0)
+	if age := time.Since(signedAt); age > tolerance || age < -tolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, signedAt, body)), []byte(signature))
+}
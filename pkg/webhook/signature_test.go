@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It signs the timestamp and the body with HMAC-SHA256", func(t *testing.T) {
+		assert.Equal(
+			t,
+			"sha256=35192f17f5452faa293c39969f5310772938f2cdfe48f8fc7b2d112aa5f281fe",
+			Sign("key", time.Unix(1510176966, 0), []byte("The quick brown fox jumps over the lazy dog")),
+		)
+	})
+
+	t.Run("It verifies only matching signatures", func(t *testing.T) {
+		body := []byte(`{"type":"category.created"}`)
+		now := time.Now()
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+
+		assert.True(t, Verify("secret", body, timestamp, Sign("secret", now, body), time.Minute))
+		assert.False(t, Verify("other", body, timestamp, Sign("secret", now, body), time.Minute))
+		assert.False(t, Verify("secret", []byte(`{}`), timestamp, Sign("secret", now, body), time.Minute))
+		assert.False(t, Verify("secret", body, strconv.FormatInt(now.Unix()+1, 10), Sign("secret", now, body), time.Minute))
+	})
+
+	t.Run("It rejects the signatures older than the tolerance", func(t *testing.T) {
+		body := []byte(`{"type":"category.created"}`)
+		signedAt := time.Now().Add(-10 * time.Minute)
+
+		assert.False(t, Verify("secret", body, strconv.FormatInt(signedAt.Unix(), 10), Sign("secret", signedAt, body), 5*time.Minute))
+	})
+}
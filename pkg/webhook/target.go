@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ErrInternalTarget is returned when a webhook target resolves to an address of the internal networks
+var ErrInternalTarget = errors.New("The webhook target resolves to a loopback, link-local or private address")
+
+// internalNetworks are the networks the webhooks may not be sent to, so that subscribers
+// cannot make the server call the services it is the only one to reach
+var internalNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+// CheckTarget resolves the host of a webhook target and refuses it when any of its
+// addresses belongs to an internal network
+func CheckTarget(host string) error {
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("The webhook target '%s' cannot be resolved", host)
+	}
+
+	for _, ip := range ips {
+		if isInternal(ip) {
+			return ErrInternalTarget
+		}
+	}
+
+	return nil
+}
+
+// isInternal tells whether the address belongs to an internal network
+func isInternal(ip net.IP) bool {
+	if ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dialPublic dials the address the way a net.Dialer does, but only connects to the public addresses
+// of the host. The addresses are checked when connecting rather than when the request is built,
+// so that neither a redirect nor a DNS record changed in between reaches an internal network.
+func dialPublic(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, addr := range addrs {
+			if isInternal(addr.IP) {
+				return nil, ErrInternalTarget
+			}
+		}
+
+		err = fmt.Errorf("The webhook target '%s' cannot be resolved", host)
+		for _, addr := range addrs {
+			var conn net.Conn
+			if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(addr.IP.String(), port)); err == nil {
+				return conn, nil
+			}
+		}
+
+		return nil, err
+	}
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+
+	return networks
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gregbiv/news-api/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTarget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("It accepts the public addresses", func(t *testing.T) {
+		assert.NoError(t, CheckTarget("203.0.113.10"))
+		assert.NoError(t, CheckTarget("2001:db8::1"))
+	})
+
+	t.Run("It refuses the internal addresses", func(t *testing.T) {
+		for _, host := range []string{"127.0.0.1", "localhost", "10.1.2.3", "172.20.0.5", "192.168.1.1", "169.254.169.254", "::1", "fd00::1", "0.0.0.0"} {
+			assert.Equal(t, ErrInternalTarget, CheckTarget(host), host)
+		}
+	})
+
+	t.Run("It refuses the IPv4 addresses mapped to IPv6", func(t *testing.T) {
+		assert.True(t, isInternal(net.ParseIP("::ffff:127.0.0.1")))
+	})
+}
+
+func TestSenderRefusesInternalTargets(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	ping, err := NewPing(&model.Webhook{WebhookID: "0b6e2d6e-7f9c-4c55-9f0e-2e51d8b0c7a1", TargetURL: server.URL})
+	assert.NoError(t, err)
+
+	result := NewSender(time.Second).Send(ping)
+
+	assert.False(t, result.OK())
+	assert.Contains(t, result.Reason(), ErrInternalTarget.Error())
+	assert.False(t, called)
+}
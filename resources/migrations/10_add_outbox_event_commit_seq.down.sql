@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS outbox_event_commit ON outbox_event;
+
+DROP FUNCTION IF EXISTS outbox_event_commit();
+
+DROP INDEX IF EXISTS outbox_event_commit_seq_idx;
+
+ALTER TABLE outbox_event DROP COLUMN commit_seq;
+
+DROP SEQUENCE IF EXISTS outbox_event_commit_seq;
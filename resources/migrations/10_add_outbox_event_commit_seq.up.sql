@@ -0,0 +1,33 @@
+-- Event IDs are handed out in insert order, which is not the order the events become
+-- visible in. The commit sequence is assigned at commit instead, so that readers resuming
+-- after an event never miss one that was committed later with a lower ID.
+CREATE SEQUENCE IF NOT EXISTS outbox_event_commit_seq;
+
+ALTER TABLE outbox_event ADD COLUMN commit_seq BIGINT NULL;
+
+-- The events that are already there are committed, they keep their ID as sequence
+UPDATE outbox_event SET commit_seq = event_id;
+
+SELECT setval('outbox_event_commit_seq', COALESCE(MAX(event_id), 0) + 1, false) FROM outbox_event;
+
+CREATE UNIQUE INDEX outbox_event_commit_seq_idx ON outbox_event (commit_seq);
+
+CREATE OR REPLACE FUNCTION outbox_event_commit() RETURNS TRIGGER AS $$
+BEGIN
+    -- The transactions committing events are serialized until they end, so
+    -- that the sequence follows the order in which the events become visible
+    PERFORM pg_advisory_xact_lock(hashtext('outbox_event_commit'));
+
+    UPDATE outbox_event SET commit_seq = nextval('outbox_event_commit_seq') WHERE event_id = NEW.event_id;
+
+    -- Notifications are only delivered once the transaction commits,
+    -- listeners never learn about events that were rolled back
+    PERFORM pg_notify('outbox_event', NEW.event_id::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER outbox_event_commit AFTER INSERT ON outbox_event
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE outbox_event_commit();
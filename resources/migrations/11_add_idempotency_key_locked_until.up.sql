@@ -0,0 +1,4 @@
+-- The requests hold their key until they complete or their lease runs out, rather than until the key expires
+ALTER TABLE idempotency_key ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE NULL;
+
+UPDATE idempotency_key SET locked_until = created_at + interval '1 minute' WHERE status_code IS NULL;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS category (
+    category_id UUID         NOT NULL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    title       VARCHAR(255) NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE category DROP COLUMN version;
@@ -0,0 +1 @@
+ALTER TABLE category ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS category_deleted_at_idx;
+
+ALTER TABLE category DROP COLUMN deleted_at;
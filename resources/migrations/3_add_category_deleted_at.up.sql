@@ -0,0 +1,3 @@
+ALTER TABLE category ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE NULL;
+
+CREATE INDEX category_deleted_at_idx ON category (deleted_at) WHERE deleted_at IS NOT NULL;
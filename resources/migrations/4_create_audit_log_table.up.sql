@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_log_id  BIGSERIAL                NOT NULL PRIMARY KEY,
+    actor         VARCHAR(255)             NOT NULL,
+    action        VARCHAR(32)              NOT NULL,
+    resource_type VARCHAR(64)              NOT NULL,
+    resource_id   VARCHAR(64)              NOT NULL,
+    diff          JSONB                    NOT NULL DEFAULT '{}',
+    request_id    VARCHAR(255)             NOT NULL DEFAULT '',
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_resource_idx ON audit_log (resource_type, resource_id, created_at DESC);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS category_revision (
+    category_id UUID                     NOT NULL REFERENCES category (category_id) ON DELETE CASCADE,
+    revision    BIGINT                   NOT NULL,
+    name        VARCHAR(255)             NOT NULL,
+    title       VARCHAR(255)             NOT NULL,
+    actor       VARCHAR(255)             NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (category_id, revision)
+);
+
+-- Categories created before revisions were tracked start their history at their current version
+INSERT INTO category_revision (category_id, revision, name, title, actor)
+    SELECT category_id, version, name, title, 'system' FROM category;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS outbox_event (
+    event_id      BIGSERIAL                NOT NULL PRIMARY KEY,
+    event_type    VARCHAR(64)              NOT NULL,
+    resource_type VARCHAR(64)              NOT NULL,
+    resource_id   VARCHAR(64)              NOT NULL,
+    payload       JSONB                    NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    dispatched_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX outbox_event_pending_idx ON outbox_event (event_id) WHERE dispatched_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook (
+    webhook_id  UUID                     NOT NULL PRIMARY KEY,
+    target_url  TEXT                     NOT NULL,
+    event_types TEXT[]                   NOT NULL,
+    secret      VARCHAR(255)             NOT NULL,
+    active      BOOLEAN                  NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    delivery_id     BIGSERIAL                NOT NULL PRIMARY KEY,
+    webhook_id      UUID                     NOT NULL REFERENCES webhook (webhook_id) ON DELETE CASCADE,
+    event_id        BIGINT                   NOT NULL REFERENCES outbox_event (event_id) ON DELETE CASCADE,
+    status          VARCHAR(16)              NOT NULL DEFAULT 'pending',
+    attempts        INTEGER                  NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_error      TEXT                     NOT NULL DEFAULT '',
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX webhook_delivery_due_idx ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_delivery_status_idx ON webhook_delivery (status, delivery_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS webhook_attempt (
+    attempt_id       BIGSERIAL                NOT NULL PRIMARY KEY,
+    webhook_id       UUID                     NOT NULL REFERENCES webhook (webhook_id) ON DELETE CASCADE,
+    delivery_id      BIGINT                   NULL REFERENCES webhook_delivery (delivery_id) ON DELETE CASCADE,
+    event_type       VARCHAR(64)              NOT NULL,
+    status_code      INTEGER                  NULL,
+    latency_ms       INTEGER                  NOT NULL DEFAULT 0,
+    response_excerpt TEXT                     NOT NULL DEFAULT '',
+    error            TEXT                     NOT NULL DEFAULT '',
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhook_attempt_webhook_idx ON webhook_attempt (webhook_id, attempt_id);
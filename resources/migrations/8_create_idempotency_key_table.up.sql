@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    scope           VARCHAR(255)             NOT NULL,
+    idempotency_key VARCHAR(255)             NOT NULL,
+    request_hash    CHAR(64)                 NOT NULL,
+    status_code     INTEGER                  NULL,
+    response_header JSONB                    NULL,
+    response_body   BYTEA                    NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX idempotency_key_expires_at_idx ON idempotency_key (expires_at);
@@ -0,0 +1 @@
+ALTER TABLE audit_log ADD COLUMN claimed_actor VARCHAR(255) NOT NULL DEFAULT '';
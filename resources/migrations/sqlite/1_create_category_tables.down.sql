@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS outbox_event;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS category_revision;
+DROP TABLE IF EXISTS category;
@@ -0,0 +1,43 @@
+-- SQLite only serves categories, along with their history and the events of their changes
+CREATE TABLE IF NOT EXISTS category (
+    category_id VARCHAR(36)  NOT NULL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    version     BIGINT       NOT NULL DEFAULT 1,
+    deleted_at  TIMESTAMP    NULL
+);
+
+CREATE INDEX category_deleted_at_idx ON category (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS category_revision (
+    category_id VARCHAR(36)  NOT NULL REFERENCES category (category_id) ON DELETE CASCADE,
+    revision    BIGINT       NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    actor       VARCHAR(255) NOT NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (category_id, revision)
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_log_id  INTEGER      NOT NULL PRIMARY KEY AUTOINCREMENT,
+    actor         VARCHAR(255) NOT NULL,
+    action        VARCHAR(32)  NOT NULL,
+    resource_type VARCHAR(64)  NOT NULL,
+    resource_id   VARCHAR(64)  NOT NULL,
+    diff          TEXT         NOT NULL DEFAULT '{}',
+    request_id    VARCHAR(255) NOT NULL DEFAULT '',
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_resource_idx ON audit_log (resource_type, resource_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS outbox_event (
+    event_id      INTEGER     NOT NULL PRIMARY KEY AUTOINCREMENT,
+    event_type    VARCHAR(64) NOT NULL,
+    resource_type VARCHAR(64) NOT NULL,
+    resource_id   VARCHAR(64) NOT NULL,
+    payload       TEXT        NOT NULL,
+    created_at    TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP   NULL
+);
@@ -0,0 +1,20 @@
+-- SQLite cannot drop columns, so the table is rebuilt without it
+CREATE TABLE audit_log_without_claimed_actor (
+    audit_log_id  INTEGER      NOT NULL PRIMARY KEY AUTOINCREMENT,
+    actor         VARCHAR(255) NOT NULL,
+    action        VARCHAR(32)  NOT NULL,
+    resource_type VARCHAR(64)  NOT NULL,
+    resource_id   VARCHAR(64)  NOT NULL,
+    diff          TEXT         NOT NULL DEFAULT '{}',
+    request_id    VARCHAR(255) NOT NULL DEFAULT '',
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO audit_log_without_claimed_actor
+SELECT audit_log_id, actor, action, resource_type, resource_id, diff, request_id, created_at FROM audit_log;
+
+DROP TABLE audit_log;
+
+ALTER TABLE audit_log_without_claimed_actor RENAME TO audit_log;
+
+CREATE INDEX audit_log_resource_idx ON audit_log (resource_type, resource_id, created_at DESC);